@@ -0,0 +1,116 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/1password/onepassword-sdk-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeVaults struct {
+	onepassword.VaultsAPI
+}
+
+func (fakeVaults) GetOverview(ctx context.Context, vaultID string) (onepassword.VaultOverview, error) {
+	return onepassword.VaultOverview{ID: vaultID, Title: "Production"}, nil
+}
+
+type fakeItems struct {
+	onepassword.ItemsAPI
+	items []onepassword.Item
+	files map[string][]byte
+}
+
+func (f fakeItems) List(ctx context.Context, vaultID string, filters ...onepassword.ItemListFilter) ([]onepassword.ItemOverview, error) {
+	var overviews []onepassword.ItemOverview
+	for _, item := range f.items {
+		overviews = append(overviews, onepassword.ItemOverview{ID: item.ID, VaultID: item.VaultID, Title: item.Title})
+	}
+	return overviews, nil
+}
+
+func (f fakeItems) GetAll(ctx context.Context, vaultID string, itemIds []string) (onepassword.ItemsGetAllResponse, error) {
+	var response onepassword.ItemsGetAllResponse
+	for _, item := range f.items {
+		response.IndividualResponses = append(response.IndividualResponses, onepassword.Response[onepassword.Item, onepassword.ItemsGetAllError]{Content: &item})
+	}
+	return response, nil
+}
+
+func (f fakeItems) Files() onepassword.ItemsFilesAPI {
+	return fakeFiles{files: f.files}
+}
+
+type fakeFiles struct {
+	onepassword.ItemsFilesAPI
+	files map[string][]byte
+}
+
+func (f fakeFiles) Read(ctx context.Context, vaultID string, itemID string, attr onepassword.FileAttributes) ([]byte, error) {
+	return f.files[attr.ID], nil
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	items := fakeItems{
+		items: []onepassword.Item{
+			{
+				ID:       "item1",
+				VaultID:  "vault1",
+				Title:    "Database",
+				Category: onepassword.ItemCategoryDatabase,
+				Fields:   []onepassword.ItemField{{ID: "password", Title: "password", FieldType: onepassword.ItemFieldTypeConcealed, Value: "hunter2"}},
+				Files:    []onepassword.ItemFile{{Attributes: onepassword.FileAttributes{ID: "file1", Name: "ca.pem"}, SectionID: "certs", FieldID: "ca"}},
+			},
+			{
+				ID:       "item2",
+				VaultID:  "vault1",
+				Title:    "Keystore",
+				Category: onepassword.ItemCategoryDocument,
+				Document: &onepassword.FileAttributes{ID: "doc1", Name: "keystore.jks"},
+			},
+		},
+		files: map[string][]byte{"file1": []byte("-----BEGIN CERTIFICATE-----"), "doc1": {0xfe, 0xed, 0xfe, 0xed}},
+	}
+	client := &onepassword.Client{ItemsAPI: items, VaultsAPI: fakeVaults{}}
+
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = Export(ctx, client, []string{"vault1"}, &buf, ExportOptions{Recipients: []string{identity.Recipient().String()}})
+	require.NoError(t, err)
+
+	_, err = Open(bytes.NewReader(buf.Bytes()), OpenOptions{})
+	assert.Error(t, err, "encrypted archive must not be readable without an identity")
+
+	reader, err := Open(bytes.NewReader(buf.Bytes()), OpenOptions{Identities: []string{identity.String()}})
+	require.NoError(t, err)
+	assert.Equal(t, FormatVersion, reader.Manifest.FormatVersion)
+	require.Len(t, reader.Manifest.Vaults, 1)
+	assert.Equal(t, "Production", reader.Manifest.Vaults[0].Title)
+
+	first, err := reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "Database", first.Item.Title)
+	assert.Equal(t, []byte("-----BEGIN CERTIFICATE-----"), first.FileContents["file1"])
+
+	params, err := first.CreateParams(ctx, "vault2")
+	require.NoError(t, err)
+	assert.Equal(t, "vault2", params.VaultID)
+	require.Len(t, params.Files, 1)
+	assert.Equal(t, "ca.pem", params.Files[0].Name)
+	assert.Equal(t, "certs", params.Files[0].SectionID)
+
+	second, err := reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xfe, 0xed, 0xfe, 0xed}, second.DocumentContent)
+
+	_, err = reader.Next()
+	assert.ErrorIs(t, err, io.EOF)
+}
@@ -0,0 +1,217 @@
+// Package archive writes 1Password vaults into portable, versioned backup archives and reads them back.
+//
+// An archive is a gzip compressed tar stream, optionally encrypted with age (https://age-encryption.org) to
+// X25519 recipients or a passphrase, so it can also be decrypted with the age command line tool. It holds:
+//
+//	manifest.json                                  the format version, creation time and exported vaults
+//	vaults/<vault ID>/items/<item ID>/item.json    the item, as returned by Items().Get
+//	vaults/<vault ID>/items/<item ID>/files/<ID>   the content of each file attachment
+//	vaults/<vault ID>/items/<item ID>/document     the content of the document of Document items
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"filippo.io/age"
+	"github.com/1password/onepassword-sdk-go"
+)
+
+// FormatVersion is the version of the archive layout written by Export.
+const FormatVersion = 1
+
+const (
+	manifestName = "manifest.json"
+	itemName     = "item.json"
+	documentName = "document"
+	filesDir     = "files"
+)
+
+// Manifest describes the contents of an archive.
+type Manifest struct {
+	// The version of the archive layout
+	FormatVersion int `json:"formatVersion"`
+	// The time the archive was created at
+	CreatedAt time.Time `json:"createdAt"`
+	// The exported vaults
+	Vaults []onepassword.VaultOverview `json:"vaults"`
+}
+
+// ExportOptions configure Export.
+type ExportOptions struct {
+	// Encrypt the archive to these age X25519 recipients, e.g. "age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p".
+	Recipients []string
+	// Encrypt the archive with this passphrase. Can't be combined with Recipients.
+	Passphrase string
+	// Also export archived items.
+	IncludeArchived bool
+}
+
+// Export writes all items of the given vaults, including the content of their files and documents, into an archive.
+// The export fails as a whole if any item or file can't be read, so that a successfully written archive is complete.
+func Export(ctx context.Context, client *onepassword.Client, vaultIDs []string, w io.Writer, opts ExportOptions) error {
+	encrypted, err := encrypt(w, opts)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(encrypted)
+	tw := tar.NewWriter(gz)
+	createdAt := time.Now().UTC()
+
+	manifest := Manifest{FormatVersion: FormatVersion, CreatedAt: createdAt}
+	for _, vaultID := range vaultIDs {
+		overview, err := client.Vaults().GetOverview(ctx, vaultID)
+		if err != nil {
+			return fmt.Errorf("error getting vault %s: %w", vaultID, err)
+		}
+		manifest.Vaults = append(manifest.Vaults, overview)
+	}
+	if err := writeJSON(tw, manifestName, manifest, createdAt); err != nil {
+		return err
+	}
+
+	for _, vaultID := range vaultIDs {
+		if err := exportVault(ctx, client, vaultID, tw, opts, createdAt); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return encrypted.Close()
+}
+
+func exportVault(ctx context.Context, client *onepassword.Client, vaultID string, tw *tar.Writer, opts ExportOptions, modTime time.Time) error {
+	filter := onepassword.NewItemListFilterTypeVariantByState(&onepassword.ItemListFilterByStateInner{
+		Active:   true,
+		Archived: opts.IncludeArchived,
+	})
+	overviews, err := client.Items().List(ctx, vaultID, filter)
+	if err != nil {
+		return fmt.Errorf("error listing items of vault %s: %w", vaultID, err)
+	}
+	if len(overviews) == 0 {
+		return nil
+	}
+
+	itemIDs := make([]string, len(overviews))
+	for i, overview := range overviews {
+		itemIDs[i] = overview.ID
+	}
+	items, err := client.Items().GetAll(ctx, vaultID, itemIDs)
+	if err != nil {
+		return fmt.Errorf("error getting items of vault %s: %w", vaultID, err)
+	}
+
+	for i, response := range items.IndividualResponses {
+		if response.Error != nil {
+			return fmt.Errorf("error getting item %s of vault %s: %s", itemIDs[i], vaultID, response.Error.Type)
+		}
+		if err := exportItem(ctx, client, *response.Content, tw, modTime); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func exportItem(ctx context.Context, client *onepassword.Client, item onepassword.Item, tw *tar.Writer, modTime time.Time) error {
+	dir := itemDir(item.VaultID, item.ID)
+	if err := writeJSON(tw, path.Join(dir, itemName), item, modTime); err != nil {
+		return err
+	}
+
+	for _, file := range item.Files {
+		content, err := client.Items().Files().Read(ctx, item.VaultID, item.ID, file.Attributes)
+		if err != nil {
+			return fmt.Errorf("error reading file %q of item %s: %w", file.Attributes.Name, item.ID, err)
+		}
+		if err := writeFile(tw, path.Join(dir, filesDir, file.Attributes.ID), content, modTime); err != nil {
+			return err
+		}
+	}
+
+	if item.Document != nil {
+		content, err := client.Items().Files().Read(ctx, item.VaultID, item.ID, *item.Document)
+		if err != nil {
+			return fmt.Errorf("error reading document %q of item %s: %w", item.Document.Name, item.ID, err)
+		}
+		if err := writeFile(tw, path.Join(dir, documentName), content, modTime); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encrypt wraps w according to the encryption options. The returned writer must be closed to flush the encryption.
+func encrypt(w io.Writer, opts ExportOptions) (io.WriteCloser, error) {
+	if opts.Passphrase != "" && len(opts.Recipients) > 0 {
+		return nil, errors.New("an archive can be encrypted either to recipients or with a passphrase, not both")
+	}
+
+	var recipients []age.Recipient
+	for _, r := range opts.Recipients {
+		recipient, err := age.ParseX25519Recipient(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient %q: %w", r, err)
+		}
+		recipients = append(recipients, recipient)
+	}
+	if opts.Passphrase != "" {
+		recipient, err := age.NewScryptRecipient(opts.Passphrase)
+		if err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	if len(recipients) == 0 {
+		return nopWriteCloser{w}, nil
+	}
+	return age.Encrypt(w, recipients...)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+func itemDir(vaultID string, itemID string) string {
+	return path.Join("vaults", vaultID, "items", itemID)
+}
+
+func writeJSON(tw *tar.Writer, name string, v any, modTime time.Time) error {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return writeFile(tw, name, content, modTime)
+}
+
+func writeFile(tw *tar.Writer, name string, content []byte, modTime time.Time) error {
+	err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     int64(len(content)),
+		Mode:     0o600,
+		ModTime:  modTime,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = tw.Write(content)
+	return err
+}
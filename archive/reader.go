@@ -0,0 +1,174 @@
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/1password/onepassword-sdk-go"
+)
+
+// OpenOptions configure Open.
+type OpenOptions struct {
+	// Decrypt the archive with these age X25519 identities, e.g. "AGE-SECRET-KEY-1...".
+	Identities []string
+	// Decrypt the archive with this passphrase.
+	Passphrase string
+}
+
+// Entry is an item read from an archive, together with the content of its files.
+type Entry struct {
+	// The item, as it was exported
+	Item onepassword.Item
+	// The content of the item's file attachments, keyed by file ID
+	FileContents map[string][]byte
+	// The content of the item's document, for Document items
+	DocumentContent []byte
+}
+
+// CreateParams returns the parameters to recreate the archived item, including its files and document, in the given vault.
+func (e *Entry) CreateParams(ctx context.Context, vaultID string) (onepassword.ItemCreateParams, error) {
+	params, err := onepassword.ItemCreateParamsFromItem(ctx, e.Item, e.readFile)
+	if err != nil {
+		return onepassword.ItemCreateParams{}, err
+	}
+	params.VaultID = vaultID
+	return params, nil
+}
+
+// Reader reads the items of an archive one at a time.
+type Reader struct {
+	// The manifest of the archive
+	Manifest Manifest
+
+	tr      *tar.Reader
+	pending *tar.Header
+}
+
+// Open reads the manifest of an archive written by Export and returns a Reader for its items.
+// Identities or a passphrase must be given for encrypted archives.
+func Open(r io.Reader, opts OpenOptions) (*Reader, error) {
+	decrypted, err := decrypt(r, opts)
+	if err != nil {
+		return nil, err
+	}
+	gz, err := gzip.NewReader(decrypted)
+	if err != nil {
+		return nil, fmt.Errorf("not a 1Password archive, or it is encrypted: %w", err)
+	}
+
+	reader := &Reader{tr: tar.NewReader(gz)}
+	header, err := reader.tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("error reading archive manifest: %w", err)
+	}
+	if header.Name != manifestName {
+		return nil, fmt.Errorf("expected archive to start with %s, found %s", manifestName, header.Name)
+	}
+	if err := json.NewDecoder(reader.tr).Decode(&reader.Manifest); err != nil {
+		return nil, fmt.Errorf("error reading archive manifest: %w", err)
+	}
+	if reader.Manifest.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("archive format version %d is newer than the supported version %d", reader.Manifest.FormatVersion, FormatVersion)
+	}
+	return reader, nil
+}
+
+// Next returns the next item of the archive, or io.EOF once all items were read.
+func (r *Reader) Next() (*Entry, error) {
+	header, err := r.next()
+	if err != nil {
+		return nil, err
+	}
+	if path.Base(header.Name) != itemName {
+		return nil, fmt.Errorf("unexpected archive entry %s", header.Name)
+	}
+
+	entry := &Entry{FileContents: map[string][]byte{}}
+	if err := json.NewDecoder(r.tr).Decode(&entry.Item); err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", header.Name, err)
+	}
+	dir := path.Dir(header.Name)
+
+	for {
+		header, err := r.next()
+		if errors.Is(err, io.EOF) {
+			return entry, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(header.Name, dir+"/") || path.Base(header.Name) == itemName {
+			r.pending = header
+			return entry, nil
+		}
+
+		content, err := io.ReadAll(r.tr)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", header.Name, err)
+		}
+		switch rel := strings.TrimPrefix(header.Name, dir+"/"); {
+		case rel == documentName:
+			entry.DocumentContent = content
+		case path.Dir(rel) == filesDir:
+			entry.FileContents[path.Base(rel)] = content
+		default:
+			return nil, fmt.Errorf("unexpected archive entry %s", header.Name)
+		}
+	}
+}
+
+func (r *Reader) next() (*tar.Header, error) {
+	if r.pending != nil {
+		header := r.pending
+		r.pending = nil
+		return header, nil
+	}
+	return r.tr.Next()
+}
+
+func decrypt(r io.Reader, opts OpenOptions) (io.Reader, error) {
+	var identities []age.Identity
+	for _, i := range opts.Identities {
+		identity, err := age.ParseX25519Identity(i)
+		if err != nil {
+			return nil, fmt.Errorf("invalid identity: %w", err)
+		}
+		identities = append(identities, identity)
+	}
+	if opts.Passphrase != "" {
+		identity, err := age.NewScryptIdentity(opts.Passphrase)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, identity)
+	}
+
+	if len(identities) == 0 {
+		return r, nil
+	}
+	return age.Decrypt(r, identities...)
+}
+
+// readFile returns the archived content of one of the item's files or of its document, verified against the
+// content hash recorded in the item, if any.
+func (e *Entry) readFile(ctx context.Context, attr onepassword.FileAttributes) ([]byte, error) {
+	content, ok := e.FileContents[attr.ID]
+	if e.Item.Document != nil && attr.ID == e.Item.Document.ID {
+		content, ok = e.DocumentContent, true
+	}
+	if !ok {
+		return nil, fmt.Errorf("file %s is missing from the archive", attr.ID)
+	}
+	if err := onepassword.VerifyFileContent(e.Item, attr, content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
@@ -14,6 +14,7 @@ type Client struct {
 	VaultsAPI       VaultsAPI
 	EnvironmentsAPI EnvironmentsAPI
 	GroupsAPI       GroupsAPI
+	UsersAPI        UsersAPI
 }
 
 func initAPIs(client *Client, inner *internal.InnerClient) {
@@ -22,6 +23,7 @@ func initAPIs(client *Client, inner *internal.InnerClient) {
 	client.VaultsAPI = NewVaultsSource(inner)
 	client.EnvironmentsAPI = NewEnvironmentsSource(inner)
 	client.GroupsAPI = NewGroupsSource(inner)
+	client.UsersAPI = NewUsersSource(inner)
 }
 
 func (c *Client) Secrets() SecretsAPI {
@@ -39,3 +41,6 @@ func (c *Client) Environments() EnvironmentsAPI {
 func (c *Client) Groups() GroupsAPI {
 	return c.GroupsAPI
 }
+func (c *Client) Users() UsersAPI {
+	return c.UsersAPI
+}
@@ -59,6 +59,12 @@ func initClient(ctx context.Context, core internal.CoreWrapper, client Client) (
 	}
 
 	initAPIs(&client, &inner)
+	if items, ok := client.ItemsAPI.(*ItemsSource); ok {
+		if client.config.FileIntegrity {
+			items.FilesAPI = integrityFilesSource{files: items.FilesAPI, items: items}
+		}
+		client.ItemsAPI = batchedItemsSource{ItemsSource: items}
+	}
 
 	runtime.SetFinalizer(&client, func(f *Client) {
 		core.ReleaseClient(*clientID)
@@ -85,6 +91,17 @@ func WithIntegrationInfo(name string, version string) ClientOption {
 	}
 }
 
+// WithFileIntegrity makes the client record the SHA-256 hash of the content of every file it attaches or replaces
+// in the item, and verify the content of every file it reads against the hash recorded for it. Reads return a
+// *FileIntegrityError if the content doesn't match. Each read fetches the item first to find the recorded hash.
+// Hashes of files saved with new items are only recorded if the parameters are passed through WithFileHashes.
+func WithFileIntegrity() ClientOption {
+	return func(c *Client) error {
+		c.config.FileIntegrity = true
+		return nil
+	}
+}
+
 func clientInvoke(ctx context.Context, innerClient *internal.InnerClient, invocation string, params map[string]interface{}) (*string, error) {
 	invocationResponse, err := innerClient.Core.Invoke(ctx, internal.InvokeConfig{
 		Invocation: internal.Invocation{
@@ -96,7 +113,7 @@ func clientInvoke(ctx context.Context, innerClient *internal.InnerClient, invoca
 		},
 	})
 	if err != nil {
-		err = unmarshalError(err.Error())
+		err = unmarshalInvokeError(err.Error())
 		var e *DesktopSessionExpiredError
 		if errors.As(err, &e) {
 			var clientID *uint64
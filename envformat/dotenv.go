@@ -0,0 +1,163 @@
+package envformat
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/1password/onepassword-sdk-go"
+)
+
+// ParseDotenv reads the variables of a .env file, to import them into an Environment with SetVariables.
+//
+// Lines hold NAME=value, optionally prefixed with "export ". Blank lines and lines starting with "#" are skipped.
+// Values can be unquoted, with a trailing " #" comment removed, single quoted and taken literally, or double
+// quoted with the backslash escapes \n, \r, \t, \\, \", \$ and \`. Quoted values can span several lines.
+// Values are not interpolated. If a name is set more than once, the last value is kept.
+//
+// All variables are returned masked, as .env files mostly hold secrets; unmask the ones that aren't before
+// importing them.
+func ParseDotenv(r io.Reader) ([]onepassword.EnvironmentVariable, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	p := &dotenvParser{input: strings.ReplaceAll(string(content), "\r\n", "\n"), line: 1}
+
+	var variables []onepassword.EnvironmentVariable
+	index := map[string]int{}
+	for {
+		name, value, ok, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return variables, nil
+		}
+		v := onepassword.EnvironmentVariable{Name: name, Value: value, Masked: true}
+		if i, ok := index[name]; ok {
+			variables[i] = v
+			continue
+		}
+		index[name] = len(variables)
+		variables = append(variables, v)
+	}
+}
+
+type dotenvParser struct {
+	input string
+	pos   int
+	line  int
+}
+
+func (p *dotenvParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+// readLine returns the rest of the current line and moves to the next one.
+func (p *dotenvParser) readLine() string {
+	rest := p.input[p.pos:]
+	end := strings.IndexByte(rest, '\n')
+	if end < 0 {
+		p.pos = len(p.input)
+		return rest
+	}
+	p.pos += end + 1
+	p.line++
+	return rest[:end]
+}
+
+// skipSpace moves past spaces and tabs.
+func (p *dotenvParser) skipSpace() {
+	for p.pos < len(p.input) && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// next returns the next variable, or false at the end of the input.
+func (p *dotenvParser) next() (string, string, bool, error) {
+	for p.pos < len(p.input) {
+		p.skipSpace()
+		if p.pos == len(p.input) {
+			break
+		}
+		if p.input[p.pos] == '\n' || p.input[p.pos] == '#' {
+			p.readLine()
+			continue
+		}
+		if strings.HasPrefix(p.input[p.pos:], "export ") {
+			p.pos += len("export ")
+		}
+		eq := strings.IndexByte(p.input[p.pos:], '=')
+		newline := strings.IndexByte(p.input[p.pos:], '\n')
+		if eq < 0 || (newline >= 0 && newline < eq) {
+			return "", "", false, p.errorf("expected NAME=value")
+		}
+		name := strings.TrimSpace(p.input[p.pos : p.pos+eq])
+		if !validName.MatchString(name) {
+			return "", "", false, p.errorf("invalid environment variable name %q", name)
+		}
+		p.pos += eq + 1
+		p.skipSpace()
+
+		value, err := p.value()
+		if err != nil {
+			return "", "", false, err
+		}
+		return name, value, true, nil
+	}
+	return "", "", false, nil
+}
+
+// value reads a value and the rest of its line.
+func (p *dotenvParser) value() (string, error) {
+	if p.pos >= len(p.input) || (p.input[p.pos] != '\'' && p.input[p.pos] != '"') {
+		value := p.readLine()
+		if i := strings.Index(value, " #"); i >= 0 {
+			value = value[:i]
+		}
+		return strings.TrimSpace(value), nil
+	}
+
+	quote := p.input[p.pos]
+	p.pos++
+	var b strings.Builder
+	for {
+		if p.pos >= len(p.input) {
+			return "", p.errorf("unterminated quoted value")
+		}
+		c := p.input[p.pos]
+		p.pos++
+		switch {
+		case c == quote:
+			line := p.line
+			rest := strings.TrimSpace(p.readLine())
+			if rest != "" && !strings.HasPrefix(rest, "#") {
+				p.line = line
+				return "", p.errorf("unexpected %q after quoted value", rest)
+			}
+			return b.String(), nil
+		case c == '\n':
+			p.line++
+			b.WriteByte(c)
+		case c == '\\' && quote == '"' && p.pos < len(p.input):
+			escaped := p.input[p.pos]
+			p.pos++
+			switch escaped {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '\\', '"', '$', '`':
+				b.WriteByte(escaped)
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(escaped)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+}
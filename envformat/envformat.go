@@ -0,0 +1,119 @@
+// Package envformat writes the variables of a 1Password Environment in the formats used to load them into
+// processes and clusters, and reads .env files back into variables.
+//
+// The shell formats quote every value so that it is taken literally, including values spanning several lines.
+// Variables are written in the order they're given.
+package envformat
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/1password/onepassword-sdk-go"
+)
+
+// validName matches the variable names that can be set from a shell.
+var validName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// checkNames returns an error for the first variable with a name that can't be used as an environment variable.
+func checkNames(variables []onepassword.EnvironmentVariable) error {
+	for _, v := range variables {
+		if !validName.MatchString(v.Name) {
+			return fmt.Errorf("invalid environment variable name %q", v.Name)
+		}
+	}
+	return nil
+}
+
+// write checks the variable names and writes a line for each variable.
+func write(w io.Writer, env onepassword.GetVariablesResponse, line func(onepassword.EnvironmentVariable) string) error {
+	if err := checkNames(env.Variables); err != nil {
+		return err
+	}
+	var b strings.Builder
+	for _, v := range env.Variables {
+		b.WriteString(line(v))
+		b.WriteByte('\n')
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteDotenv writes the variables as a .env file, which can be read back with ParseDotenv.
+//
+// Values are left unquoted if they only hold characters that need no quoting, single quoted if they hold no
+// single quote or line break, and double quoted with backslash escapes otherwise. "$" is escaped in double
+// quoted values so that loaders don't interpolate it.
+func WriteDotenv(w io.Writer, env onepassword.GetVariablesResponse) error {
+	return write(w, env, func(v onepassword.EnvironmentVariable) string {
+		return v.Name + "=" + quoteDotenv(v.Value)
+	})
+}
+
+var unquotedDotenv = regexp.MustCompile(`^[A-Za-z0-9_./:@%+,=-]*$`)
+
+func quoteDotenv(value string) string {
+	if unquotedDotenv.MatchString(value) {
+		return value
+	}
+	if !strings.ContainsAny(value, "'\n\r") {
+		return "'" + value + "'"
+	}
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range value {
+		switch r {
+		case '\\', '"', '$', '`':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// WriteShell writes the variables as POSIX shell export statements, to be evaluated with eval or source.
+func WriteShell(w io.Writer, env onepassword.GetVariablesResponse) error {
+	return write(w, env, func(v onepassword.EnvironmentVariable) string {
+		return "export " + v.Name + "='" + strings.ReplaceAll(v.Value, "'", `'\''`) + "'"
+	})
+}
+
+// WriteFish writes the variables as fish shell set statements.
+func WriteFish(w io.Writer, env onepassword.GetVariablesResponse) error {
+	escaper := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	return write(w, env, func(v onepassword.EnvironmentVariable) string {
+		return "set -gx " + v.Name + " '" + escaper.Replace(v.Value) + "'"
+	})
+}
+
+// WritePowerShell writes the variables as PowerShell assignments to $env:.
+// Single quotes are doubled, including the curly quotes U+2018 to U+201B, which PowerShell also treats as single quotes.
+func WritePowerShell(w io.Writer, env onepassword.GetVariablesResponse) error {
+	escaper := strings.NewReplacer("'", "''", "\u2018", "\u2018\u2018", "\u2019", "\u2019\u2019", "\u201a", "\u201a\u201a", "\u201b", "\u201b\u201b")
+	return write(w, env, func(v onepassword.EnvironmentVariable) string {
+		return "$env:" + v.Name + " = '" + escaper.Replace(v.Value) + "'"
+	})
+}
+
+// WriteJSON writes the variables as a JSON object mapping each name to its value.
+func WriteJSON(w io.Writer, env onepassword.GetVariablesResponse) error {
+	values := make(map[string]string, len(env.Variables))
+	for _, v := range env.Variables {
+		values[v.Name] = v.Value
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(values)
+}
@@ -0,0 +1,100 @@
+package envformat
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/1password/onepassword-sdk-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var env = onepassword.GetVariablesResponse{Variables: []onepassword.EnvironmentVariable{
+	{Name: "HOST", Value: "db.example.com:5432"},
+	{Name: "PASSWORD", Value: "it's $ecret", Masked: true},
+	{Name: "CERT", Value: "-----BEGIN-----\nabc\\def\n-----END-----", Masked: true},
+}}
+
+func TestWriteDotenv(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, WriteDotenv(&b, env))
+	assert.Equal(t, `HOST=db.example.com:5432
+PASSWORD="it's \$ecret"
+CERT="-----BEGIN-----\nabc\\def\n-----END-----"
+`, b.String())
+
+	parsed, err := ParseDotenv(&b)
+	require.NoError(t, err)
+	for i := range parsed {
+		parsed[i].Masked = env.Variables[i].Masked
+	}
+	assert.Equal(t, env.Variables, parsed)
+}
+
+func TestParseDotenv(t *testing.T) {
+	parsed, err := ParseDotenv(strings.NewReader(`# comment
+export A=plain value # trailing comment
+B = 'single $quoted'
+
+C="multi
+line"
+A=again
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []onepassword.EnvironmentVariable{
+		{Name: "A", Value: "again", Masked: true},
+		{Name: "B", Value: "single $quoted", Masked: true},
+		{Name: "C", Value: "multi\nline", Masked: true},
+	}, parsed)
+
+	_, err = ParseDotenv(strings.NewReader("A=1\nB='unterminated\n"))
+	assert.EqualError(t, err, "line 3: unterminated quoted value")
+	_, err = ParseDotenv(strings.NewReader("A=1\nB='x' y\n"))
+	assert.EqualError(t, err, `line 2: unexpected "y" after quoted value`)
+}
+
+func TestWriteShells(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, WriteShell(&b, env))
+	assert.Contains(t, b.String(), `export PASSWORD='it'\''s $ecret'`+"\n")
+
+	b.Reset()
+	require.NoError(t, WriteFish(&b, env))
+	assert.Contains(t, b.String(), `set -gx CERT '-----BEGIN-----`+"\n"+`abc\\def`)
+
+	b.Reset()
+	require.NoError(t, WritePowerShell(&b, env))
+	assert.Contains(t, b.String(), `$env:PASSWORD = 'it''s $ecret'`+"\n")
+
+	b.Reset()
+	quotes := onepassword.GetVariablesResponse{Variables: []onepassword.EnvironmentVariable{{Name: "QUOTES", Value: "\u2018a\u2019 \u201ab\u201b"}}}
+	require.NoError(t, WritePowerShell(&b, quotes))
+	assert.Equal(t, "$env:QUOTES = '\u2018\u2018a\u2019\u2019 \u201a\u201ab\u201b\u201b'\n", b.String())
+
+	err := WriteShell(&b, onepassword.GetVariablesResponse{Variables: []onepassword.EnvironmentVariable{{Name: "NOT-VALID"}}})
+	assert.EqualError(t, err, `invalid environment variable name "NOT-VALID"`)
+}
+
+func TestWriteKubernetes(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, WriteKubernetes(&b, env, KubernetesOptions{Name: "app", Namespace: "prod"}))
+	assert.Equal(t, `apiVersion: v1
+kind: Secret
+metadata:
+  name: app
+  namespace: prod
+type: Opaque
+data:
+  CERT: LS0tLS1CRUdJTi0tLS0tCmFiY1xkZWYKLS0tLS1FTkQtLS0tLQ==
+  PASSWORD: aXQncyAkZWNyZXQ=
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+  namespace: prod
+data:
+  HOST: db.example.com:5432
+`, b.String())
+}
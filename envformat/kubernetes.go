@@ -0,0 +1,72 @@
+package envformat
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"regexp"
+
+	"github.com/1password/onepassword-sdk-go"
+	"gopkg.in/yaml.v3"
+)
+
+// KubernetesOptions configures WriteKubernetes.
+type KubernetesOptions struct {
+	// The name of the Secret and ConfigMap
+	Name string
+	// The namespace of the Secret and ConfigMap, left out if empty
+	Namespace string
+	// Labels added to the Secret and ConfigMap
+	Labels map[string]string
+}
+
+type kubernetesMetadata struct {
+	Name      string            `yaml:"name"`
+	Namespace string            `yaml:"namespace,omitempty"`
+	Labels    map[string]string `yaml:"labels,omitempty"`
+}
+
+type kubernetesObject struct {
+	APIVersion string             `yaml:"apiVersion"`
+	Kind       string             `yaml:"kind"`
+	Metadata   kubernetesMetadata `yaml:"metadata"`
+	Type       string             `yaml:"type,omitempty"`
+	Data       map[string]string  `yaml:"data"`
+}
+
+// validKey matches the keys allowed in the data of a Secret or ConfigMap.
+var validKey = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+
+// WriteKubernetes writes the variables as Kubernetes manifests: masked variables go into an Opaque Secret and
+// unmasked variables into a ConfigMap, both named after opts.Name. Each is only written if it has any variables;
+// when both are, they're separated by "---".
+func WriteKubernetes(w io.Writer, env onepassword.GetVariablesResponse, opts KubernetesOptions) error {
+	if opts.Name == "" {
+		return fmt.Errorf("a name is required for the Kubernetes manifests")
+	}
+	metadata := kubernetesMetadata{Name: opts.Name, Namespace: opts.Namespace, Labels: opts.Labels}
+	secret := kubernetesObject{APIVersion: "v1", Kind: "Secret", Metadata: metadata, Type: "Opaque", Data: map[string]string{}}
+	configMap := kubernetesObject{APIVersion: "v1", Kind: "ConfigMap", Metadata: metadata, Data: map[string]string{}}
+	for _, v := range env.Variables {
+		if !validKey.MatchString(v.Name) {
+			return fmt.Errorf("invalid Kubernetes data key %q", v.Name)
+		}
+		if v.Masked {
+			secret.Data[v.Name] = base64.StdEncoding.EncodeToString([]byte(v.Value))
+		} else {
+			configMap.Data[v.Name] = v.Value
+		}
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	for _, object := range []kubernetesObject{secret, configMap} {
+		if len(object.Data) == 0 {
+			continue
+		}
+		if err := encoder.Encode(object); err != nil {
+			return err
+		}
+	}
+	return encoder.Close()
+}
@@ -13,6 +13,21 @@ import (
 type EnvironmentsAPI interface {
 	// Get environment variables belonging to an Environment.
 	GetVariables(ctx context.Context, environmentID string) (GetVariablesResponse, error)
+
+	// List the Environments the client has access to.
+	List(ctx context.Context) ([]Environment, error)
+
+	// Create a new Environment.
+	Create(ctx context.Context, params EnvironmentCreateParams) (Environment, error)
+
+	// Delete an Environment by its ID.
+	Delete(ctx context.Context, environmentID string) error
+
+	// Set environment variables of an Environment. Variables are created, or updated if one with the same name exists. Other variables are left untouched.
+	SetVariables(ctx context.Context, environmentID string, variables []EnvironmentVariable) error
+
+	// Delete an environment variable from an Environment by its name.
+	DeleteVariable(ctx context.Context, environmentID string, name string) error
 }
 
 type EnvironmentsSource struct {
@@ -0,0 +1,88 @@
+package onepassword
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Represents a 1Password Environment.
+type Environment struct {
+	// The Environment's ID
+	ID string `json:"id"`
+	// The Environment's title
+	Title string `json:"title"`
+	// The Environment's description
+	Description string `json:"description"`
+	// The number of variables in the Environment
+	VariableCount uint32 `json:"variableCount"`
+	// The time the Environment was created at
+	CreatedAt time.Time `json:"createdAt"`
+	// The time the Environment was last updated at
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type EnvironmentCreateParams struct {
+	// The Environment's title
+	Title string `json:"title"`
+	// The Environment's description
+	Description *string `json:"description,omitempty"`
+	// The variables to create the Environment with
+	Variables []EnvironmentVariable `json:"variables,omitempty"`
+}
+
+// List the Environments the client has access to.
+func (e EnvironmentsSource) List(ctx context.Context) ([]Environment, error) {
+	resultString, err := clientInvoke(ctx, e.InnerClient, "EnvironmentsList", map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+	var result []Environment
+	err = json.Unmarshal([]byte(*resultString), &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Create a new Environment.
+func (e EnvironmentsSource) Create(ctx context.Context, params EnvironmentCreateParams) (Environment, error) {
+	resultString, err := clientInvoke(ctx, e.InnerClient, "EnvironmentsCreate", map[string]interface{}{
+		"params": params,
+	})
+	if err != nil {
+		return Environment{}, err
+	}
+	var result Environment
+	err = json.Unmarshal([]byte(*resultString), &result)
+	if err != nil {
+		return Environment{}, err
+	}
+	return result, nil
+}
+
+// Delete an Environment by its ID.
+func (e EnvironmentsSource) Delete(ctx context.Context, environmentID string) error {
+	_, err := clientInvoke(ctx, e.InnerClient, "EnvironmentsDelete", map[string]interface{}{
+		"environment_id": environmentID,
+	})
+	return err
+}
+
+// Set environment variables of an Environment. Variables are created, or updated if one with the same name exists. Other variables are left untouched.
+func (e EnvironmentsSource) SetVariables(ctx context.Context, environmentID string, variables []EnvironmentVariable) error {
+	_, err := clientInvoke(ctx, e.InnerClient, "EnvironmentsSetVariables", map[string]interface{}{
+		"environment_id": environmentID,
+		"variables":      variables,
+	})
+	return err
+}
+
+// Delete an environment variable from an Environment by its name.
+func (e EnvironmentsSource) DeleteVariable(ctx context.Context, environmentID string, name string) error {
+	_, err := clientInvoke(ctx, e.InnerClient, "EnvironmentsDeleteVariable", map[string]interface{}{
+		"environment_id": environmentID,
+		"name":           name,
+	})
+	return err
+}
@@ -0,0 +1,81 @@
+package onepassword
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvironmentsManage(t *testing.T) {
+	ctx := context.Background()
+	environment := `{
+		"id": "env1",
+		"title": "Production",
+		"description": "Production services",
+		"variableCount": 1,
+		"createdAt": "2024-01-01T00:00:00Z",
+		"updatedAt": "2024-01-02T00:00:00Z"
+	}`
+	core := &fakeRecordingCore{results: map[string]string{
+		"EnvironmentsList":   "[" + environment + "]",
+		"EnvironmentsCreate": environment,
+	}}
+	environments := NewEnvironmentsSource(core.inner())
+
+	list, err := environments.List(ctx)
+	require.NoError(t, err)
+	core.assertInvoked(t, "EnvironmentsList", `{}`)
+	assert.Equal(t, []Environment{{
+		ID:            "env1",
+		Title:         "Production",
+		Description:   "Production services",
+		VariableCount: 1,
+		CreatedAt:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt:     time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+	}}, list)
+
+	description := "Production services"
+	created, err := environments.Create(ctx, EnvironmentCreateParams{
+		Title:       "Production",
+		Description: &description,
+		Variables:   []EnvironmentVariable{{Name: "API_KEY", Value: "secret", Masked: true}},
+	})
+	require.NoError(t, err)
+	core.assertInvoked(t, "EnvironmentsCreate", `{"params": {
+		"title": "Production",
+		"description": "Production services",
+		"variables": [{"name": "API_KEY", "value": "secret", "masked": true}]
+	}}`)
+	assert.Equal(t, "env1", created.ID)
+
+	_, err = environments.Create(ctx, EnvironmentCreateParams{Title: "Staging"})
+	require.NoError(t, err)
+	core.assertInvoked(t, "EnvironmentsCreate", `{"params": {"title": "Staging"}}`)
+
+	require.NoError(t, environments.Delete(ctx, "env1"))
+	core.assertInvoked(t, "EnvironmentsDelete", `{"environment_id": "env1"}`)
+}
+
+func TestEnvironmentVariables(t *testing.T) {
+	ctx := context.Background()
+	core := &fakeRecordingCore{}
+	environments := NewEnvironmentsSource(core.inner())
+
+	require.NoError(t, environments.SetVariables(ctx, "env1", []EnvironmentVariable{
+		{Name: "API_KEY", Value: "secret", Masked: true},
+		{Name: "LOG_LEVEL", Value: "debug", Masked: false},
+	}))
+	core.assertInvoked(t, "EnvironmentsSetVariables", `{
+		"environment_id": "env1",
+		"variables": [
+			{"name": "API_KEY", "value": "secret", "masked": true},
+			{"name": "LOG_LEVEL", "value": "debug", "masked": false}
+		]
+	}`)
+
+	require.NoError(t, environments.DeleteVariable(ctx, "env1", "LOG_LEVEL"))
+	core.assertInvoked(t, "EnvironmentsDeleteVariable", `{"environment_id": "env1", "name": "LOG_LEVEL"}`)
+}
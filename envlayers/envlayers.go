@@ -0,0 +1,293 @@
+// Package envlayers composes the variables of several 1Password Environments into one, the way applications
+// layer their configuration, e.g. base, then staging, then a team's overrides.
+//
+// Layers are applied in order, so a variable from a later layer overrides the same variable from earlier ones.
+// Once merged, values are expanded:
+//
+//   - ${NAME} is replaced by the value of the variable NAME, which can come from any layer. Use $${NAME} for a
+//     literal ${NAME}.
+//   - A value that is a secret reference, e.g. op://Production/Database/password, is replaced by the secret.
+//     References inside longer values must be written as {{ op://... }}, like with op inject.
+//
+// Values substituted for ${NAME} are final: a secret whose value looks like a secret reference isn't resolved again.
+//
+// Each composed variable records the layer its value came from and the layers it overrides.
+package envlayers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/1password/onepassword-sdk-go"
+)
+
+// Layer is an Environment to compose.
+type Layer struct {
+	// The name of the layer, used to report where values came from, e.g. "staging"
+	Name string
+	// The ID of the Environment
+	EnvironmentID string
+}
+
+// Variable is a composed environment variable.
+type Variable struct {
+	Name string
+	// The value after interpolation and secret reference resolution
+	Value string
+	// Whether the value should be masked: set if the variable is masked in the layer its value came from, or if
+	// the value holds a secret or a masked variable
+	Masked bool
+	// The name of the layer the value came from
+	Layer string
+	// The names of the earlier layers that also set the variable, in order
+	Overrides []string
+}
+
+// Result is the outcome of composing layers.
+type Result struct {
+	// The variables, in the order they first appear in the layers
+	Variables []Variable
+}
+
+// Environment returns the composed variables in the form returned by EnvironmentsAPI.GetVariables, e.g. to be
+// written out with the envformat package.
+func (r *Result) Environment() onepassword.GetVariablesResponse {
+	variables := make([]onepassword.EnvironmentVariable, len(r.Variables))
+	for i, v := range r.Variables {
+		variables[i] = onepassword.EnvironmentVariable{Name: v.Name, Value: v.Value, Masked: v.Masked}
+	}
+	return onepassword.GetVariablesResponse{Variables: variables}
+}
+
+// Compose fetches the variables of each layer and composes them.
+func Compose(ctx context.Context, client *onepassword.Client, layers []Layer) (*Result, error) {
+	var variables []Variable
+	index := map[string]int{}
+	for _, layer := range layers {
+		env, err := client.Environments().GetVariables(ctx, layer.EnvironmentID)
+		if err != nil {
+			return nil, fmt.Errorf("error getting variables of layer %s: %w", layer.Name, err)
+		}
+		for _, v := range env.Variables {
+			i, ok := index[v.Name]
+			if !ok {
+				index[v.Name] = len(variables)
+				variables = append(variables, Variable{Name: v.Name, Value: v.Value, Masked: v.Masked, Layer: layer.Name})
+				continue
+			}
+			previous := variables[i]
+			variables[i] = Variable{Name: v.Name, Value: v.Value, Masked: v.Masked, Layer: layer.Name, Overrides: append(append([]string{}, previous.Overrides...), previous.Layer)}
+		}
+	}
+
+	// Values without ${NAME} have their references resolved first. Values with ${NAME} are then expanded in
+	// dependency order: once all the variables a value refers to are final, it is interpolated and its references,
+	// which may only be complete after interpolation, e.g. op://${VAULT}/item/field, are resolved in turn.
+	var direct, interpolated []int
+	for i, v := range variables {
+		if interpolation.MatchString(v.Value) {
+			interpolated = append(interpolated, i)
+		} else {
+			direct = append(direct, i)
+		}
+	}
+	if err := resolveReferences(ctx, client, variables, direct, nil); err != nil {
+		return nil, err
+	}
+	if err := expand(ctx, client, variables, index, interpolated); err != nil {
+		return nil, err
+	}
+	return &Result{Variables: variables}, nil
+}
+
+var (
+	// interpolation matches ${NAME}, and $${NAME} which is kept as a literal ${NAME}.
+	interpolation = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+	// wholeReference matches values that are a secret reference.
+	wholeReference = regexp.MustCompile(`^\s*(op://\S+?)\s*$`)
+	// embeddedReference matches secret references inside values.
+	embeddedReference = regexp.MustCompile(`\{\{\s*(op://[^\s}]+)\s*\}\}`)
+)
+
+// expand interpolates and then resolves the references of the given variables, in rounds of the variables whose
+// dependencies are final. The substituted values are opaque to the resolution: only references starting in the
+// variable's own text are resolved, so that secrets and other final values are never dereferenced twice.
+func expand(ctx context.Context, client *onepassword.Client, variables []Variable, index map[string]int, indices []int) error {
+	var errs []error
+	dependencies := map[int][]int{}
+	for _, i := range indices {
+		for _, m := range interpolation.FindAllStringSubmatch(variables[i].Value, -1) {
+			if strings.HasPrefix(m[0], "$$") {
+				continue
+			}
+			j, ok := index[m[1]]
+			if !ok {
+				errs = append(errs, fmt.Errorf("variable %s refers to undefined variable %s", variables[i].Name, m[1]))
+				continue
+			}
+			dependencies[i] = append(dependencies[i], j)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	pending := map[int]bool{}
+	for _, i := range indices {
+		pending[i] = true
+	}
+	for len(pending) > 0 {
+		var ready []int
+		for _, i := range indices {
+			if !pending[i] {
+				continue
+			}
+			isReady := true
+			for _, j := range dependencies[i] {
+				if pending[j] {
+					isReady = false
+					break
+				}
+			}
+			if isReady {
+				ready = append(ready, i)
+			}
+		}
+		if len(ready) == 0 {
+			var names []string
+			for _, i := range indices {
+				if pending[i] {
+					names = append(names, variables[i].Name)
+				}
+			}
+			return fmt.Errorf("variables %s refer to each other in a cycle", strings.Join(names, ", "))
+		}
+
+		substituted := map[int][]span{}
+		for _, i := range ready {
+			v := &variables[i]
+			var b strings.Builder
+			last := 0
+			for _, m := range interpolation.FindAllStringSubmatchIndex(v.Value, -1) {
+				b.WriteString(v.Value[last:m[0]])
+				last = m[1]
+				if strings.HasPrefix(v.Value[m[0]:m[1]], "$$") {
+					b.WriteString(v.Value[m[0]+1 : m[1]])
+					continue
+				}
+				dependency := variables[index[v.Value[m[2]:m[3]]]]
+				if dependency.Masked {
+					v.Masked = true
+				}
+				start := b.Len()
+				b.WriteString(dependency.Value)
+				substituted[i] = append(substituted[i], span{start: start, end: b.Len()})
+			}
+			b.WriteString(v.Value[last:])
+			v.Value = b.String()
+		}
+		if err := resolveReferences(ctx, client, variables, ready, substituted); err != nil {
+			return err
+		}
+		for _, i := range ready {
+			delete(pending, i)
+		}
+	}
+	return nil
+}
+
+// resolveReferences replaces the secret references in the values of the given variables with the secrets, in a
+// single call to ResolveAll. References starting in the opaque spans of a variable are left as they are.
+func resolveReferences(ctx context.Context, client *onepassword.Client, variables []Variable, indices []int, opaque map[int][]span) error {
+	var references []string
+	seen := map[string]bool{}
+	found := map[int][]reference{}
+	for _, i := range indices {
+		found[i] = findReferences(variables[i].Value, opaque[i])
+		for _, r := range found[i] {
+			if !seen[r.ref] {
+				seen[r.ref] = true
+				references = append(references, r.ref)
+			}
+		}
+	}
+	if len(references) == 0 {
+		return nil
+	}
+	sort.Strings(references)
+
+	response, err := client.Secrets().ResolveAll(ctx, references)
+	if err != nil {
+		return fmt.Errorf("error resolving secret references: %w", err)
+	}
+	var errs []error
+	secrets := map[string]string{}
+	for _, ref := range references {
+		resolved, ok := response.IndividualResponses[ref]
+		switch {
+		case !ok:
+			errs = append(errs, fmt.Errorf("secret reference %s was not resolved", ref))
+		case resolved.Error != nil:
+			errs = append(errs, fmt.Errorf("error resolving secret reference %s: %s", ref, resolved.Error.Type))
+		case resolved.Content != nil:
+			secrets[ref] = resolved.Content.Secret
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	for _, i := range indices {
+		if len(found[i]) == 0 {
+			continue
+		}
+		v := &variables[i]
+		var b strings.Builder
+		last := 0
+		for _, r := range found[i] {
+			b.WriteString(v.Value[last:r.start])
+			b.WriteString(secrets[r.ref])
+			last = r.end
+		}
+		b.WriteString(v.Value[last:])
+		v.Value = b.String()
+		v.Masked = true
+	}
+	return nil
+}
+
+// span is a range of bytes of a value.
+type span struct {
+	start, end int
+}
+
+// reference is a secret reference found in a value, with the range of the value the secret replaces.
+type reference struct {
+	span
+	ref string
+}
+
+// findReferences returns the secret references in a value, in order, skipping those that start in an opaque span.
+func findReferences(value string, opaque []span) []reference {
+	isOpaque := func(i int) bool {
+		return slices.ContainsFunc(opaque, func(s span) bool { return i >= s.start && i < s.end })
+	}
+	if m := wholeReference.FindStringSubmatchIndex(value); m != nil {
+		if isOpaque(m[2]) {
+			return nil
+		}
+		return []reference{{span: span{start: 0, end: len(value)}, ref: value[m[2]:m[3]]}}
+	}
+	var references []reference
+	for _, m := range embeddedReference.FindAllStringSubmatchIndex(value, -1) {
+		if !isOpaque(m[2]) {
+			references = append(references, reference{span: span{start: m[0], end: m[1]}, ref: value[m[2]:m[3]]})
+		}
+	}
+	return references
+}
@@ -0,0 +1,71 @@
+package onepassword
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/1password/onepassword-sdk-go/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseInvocation returns the name and parameters of an invocation sent to a fake core.
+func parseInvocation(invokeConfig []byte) (string, map[string]json.RawMessage, error) {
+	var config struct {
+		Invocation struct {
+			Parameters struct {
+				Name       string                     `json:"name"`
+				Parameters map[string]json.RawMessage `json:"parameters"`
+			} `json:"parameters"`
+		} `json:"invocation"`
+	}
+	if err := json.Unmarshal(invokeConfig, &config); err != nil {
+		return "", nil, err
+	}
+	return config.Invocation.Parameters.Name, config.Invocation.Parameters.Parameters, nil
+}
+
+// fakeRecordingCore records the invocations it receives, answering each with the result set for its name, or null.
+type fakeRecordingCore struct {
+	results     map[string]string
+	invocations []recordedInvocation
+}
+
+type recordedInvocation struct {
+	name   string
+	params map[string]json.RawMessage
+}
+
+func (c *fakeRecordingCore) InitClient(ctx context.Context, config []byte) ([]byte, error) {
+	return []byte("1"), nil
+}
+
+func (c *fakeRecordingCore) Invoke(ctx context.Context, invokeConfig []byte) ([]byte, error) {
+	name, params, err := parseInvocation(invokeConfig)
+	if err != nil {
+		return nil, err
+	}
+	c.invocations = append(c.invocations, recordedInvocation{name: name, params: params})
+	if result, ok := c.results[name]; ok {
+		return []byte(result), nil
+	}
+	return []byte("null"), nil
+}
+
+func (c *fakeRecordingCore) ReleaseClient(clientID []byte) {}
+
+func (c *fakeRecordingCore) inner() *internal.InnerClient {
+	return &internal.InnerClient{Core: internal.CoreWrapper{InnerCore: c}}
+}
+
+// assertInvoked checks the name and serialized parameters of the last invocation, given as a JSON object.
+func (c *fakeRecordingCore) assertInvoked(t *testing.T, name string, params string) {
+	t.Helper()
+	require.NotEmpty(t, c.invocations)
+	last := c.invocations[len(c.invocations)-1]
+	assert.Equal(t, name, last.name)
+	serialized, err := json.Marshal(last.params)
+	require.NoError(t, err)
+	assert.JSONEq(t, params, string(serialized))
+}
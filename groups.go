@@ -13,6 +13,27 @@ import (
 type GroupsAPI interface {
 	// Get a group by its ID and parameters.
 	Get(ctx context.Context, groupID string, groupParams GroupGetParams) (Group, error)
+
+	// List the groups of the account, optionally filtered by type and state.
+	List(ctx context.Context, params ...GroupListParams) ([]Group, error)
+
+	// Create a new group.
+	Create(ctx context.Context, params GroupCreateParams) (Group, error)
+
+	// Update the title or description of a group.
+	Update(ctx context.Context, groupID string, params GroupUpdateParams) (Group, error)
+
+	// Delete a group by its ID.
+	Delete(ctx context.Context, groupID string) error
+
+	// List the members of a group with their roles.
+	ListMembers(ctx context.Context, groupID string) ([]GroupMember, error)
+
+	// Add users to a group, or change the role of existing members.
+	AddMembers(ctx context.Context, groupID string, members []GroupMemberParams) error
+
+	// Remove users from a group.
+	RemoveMembers(ctx context.Context, groupID string, userIDs []string) error
 }
 
 type GroupsSource struct {
@@ -0,0 +1,146 @@
+package onepassword
+
+import (
+	"context"
+	"encoding/json"
+)
+
+type GroupListParams struct {
+	// Only list groups of this type
+	GroupType *GroupType `json:"groupType,omitempty"`
+	// Only list groups in this state
+	State *GroupState `json:"state,omitempty"`
+}
+type GroupCreateParams struct {
+	Title       string  `json:"title"`
+	Description *string `json:"description,omitempty"`
+}
+type GroupUpdateParams struct {
+	Title       *string `json:"title,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// The role of a member within a group
+type GroupRole string
+
+const (
+	// A regular member of the group
+	GroupRoleMember GroupRole = "member"
+	// A manager of the group, who can add and remove its members
+	GroupRoleManager GroupRole = "manager"
+)
+
+// A user to add to a group
+type GroupMemberParams struct {
+	// The user's ID
+	UserID string `json:"userId"`
+	// The user's role within the group
+	Role GroupRole `json:"role"`
+}
+
+// A member of a group
+type GroupMember struct {
+	// The user's ID
+	UserID string `json:"userId"`
+	// The user's name
+	Name string `json:"name"`
+	// The user's email address
+	Email string `json:"email"`
+	// The user's role within the group
+	Role GroupRole `json:"role"`
+}
+
+// List the groups of the account, optionally filtered by type and state.
+func (g GroupsSource) List(ctx context.Context, params ...GroupListParams) ([]Group, error) {
+	var param *GroupListParams
+	if len(params) > 0 {
+		param = &params[0]
+	}
+	resultString, err := clientInvoke(ctx, g.InnerClient, "GroupsList", map[string]interface{}{
+		"params": param,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var result []Group
+	err = json.Unmarshal([]byte(*resultString), &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Create a new group.
+func (g GroupsSource) Create(ctx context.Context, params GroupCreateParams) (Group, error) {
+	resultString, err := clientInvoke(ctx, g.InnerClient, "GroupsCreate", map[string]interface{}{
+		"params": params,
+	})
+	if err != nil {
+		return Group{}, err
+	}
+	var result Group
+	err = json.Unmarshal([]byte(*resultString), &result)
+	if err != nil {
+		return Group{}, err
+	}
+	return result, nil
+}
+
+// Update the title or description of a group.
+func (g GroupsSource) Update(ctx context.Context, groupID string, params GroupUpdateParams) (Group, error) {
+	resultString, err := clientInvoke(ctx, g.InnerClient, "GroupsUpdate", map[string]interface{}{
+		"group_id": groupID,
+		"params":   params,
+	})
+	if err != nil {
+		return Group{}, err
+	}
+	var result Group
+	err = json.Unmarshal([]byte(*resultString), &result)
+	if err != nil {
+		return Group{}, err
+	}
+	return result, nil
+}
+
+// Delete a group by its ID.
+func (g GroupsSource) Delete(ctx context.Context, groupID string) error {
+	_, err := clientInvoke(ctx, g.InnerClient, "GroupsDelete", map[string]interface{}{
+		"group_id": groupID,
+	})
+	return err
+}
+
+// List the members of a group with their roles.
+func (g GroupsSource) ListMembers(ctx context.Context, groupID string) ([]GroupMember, error) {
+	resultString, err := clientInvoke(ctx, g.InnerClient, "GroupsListMembers", map[string]interface{}{
+		"group_id": groupID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var result []GroupMember
+	err = json.Unmarshal([]byte(*resultString), &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Add users to a group, or change the role of existing members.
+func (g GroupsSource) AddMembers(ctx context.Context, groupID string, members []GroupMemberParams) error {
+	_, err := clientInvoke(ctx, g.InnerClient, "GroupsAddMembers", map[string]interface{}{
+		"group_id": groupID,
+		"members":  members,
+	})
+	return err
+}
+
+// Remove users from a group.
+func (g GroupsSource) RemoveMembers(ctx context.Context, groupID string, userIDs []string) error {
+	_, err := clientInvoke(ctx, g.InnerClient, "GroupsRemoveMembers", map[string]interface{}{
+		"group_id": groupID,
+		"user_ids": userIDs,
+	})
+	return err
+}
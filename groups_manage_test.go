@@ -0,0 +1,63 @@
+package onepassword
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupsManage(t *testing.T) {
+	ctx := context.Background()
+	core := &fakeRecordingCore{results: map[string]string{
+		"GroupsList":   `[{"id": "group1", "title": "Developers", "description": "", "groupType": "userDefined", "state": "active"}]`,
+		"GroupsCreate": `{"id": "group1", "title": "Developers", "description": "Engineering", "groupType": "userDefined", "state": "active"}`,
+		"GroupsUpdate": `{"id": "group1", "title": "Engineers", "description": "Engineering", "groupType": "userDefined", "state": "active"}`,
+	}}
+	groups := NewGroupsSource(core.inner())
+
+	list, err := groups.List(ctx)
+	require.NoError(t, err)
+	core.assertInvoked(t, "GroupsList", `{"params": null}`)
+	assert.Equal(t, []Group{{ID: "group1", Title: "Developers", GroupType: GroupTypeUserDefined, State: GroupStateActive}}, list)
+
+	groupType, state := GroupTypeUserDefined, GroupStateActive
+	_, err = groups.List(ctx, GroupListParams{GroupType: &groupType, State: &state})
+	require.NoError(t, err)
+	core.assertInvoked(t, "GroupsList", `{"params": {"groupType": "userDefined", "state": "active"}}`)
+
+	description := "Engineering"
+	group, err := groups.Create(ctx, GroupCreateParams{Title: "Developers", Description: &description})
+	require.NoError(t, err)
+	core.assertInvoked(t, "GroupsCreate", `{"params": {"title": "Developers", "description": "Engineering"}}`)
+	assert.Equal(t, "group1", group.ID)
+
+	title := "Engineers"
+	group, err = groups.Update(ctx, "group1", GroupUpdateParams{Title: &title})
+	require.NoError(t, err)
+	core.assertInvoked(t, "GroupsUpdate", `{"group_id": "group1", "params": {"title": "Engineers"}}`)
+	assert.Equal(t, "Engineers", group.Title)
+
+	require.NoError(t, groups.Delete(ctx, "group1"))
+	core.assertInvoked(t, "GroupsDelete", `{"group_id": "group1"}`)
+}
+
+func TestGroupMembers(t *testing.T) {
+	ctx := context.Background()
+	core := &fakeRecordingCore{results: map[string]string{
+		"GroupsListMembers": `[{"userId": "user1", "name": "Jane", "email": "jane@example.com", "role": "manager"}]`,
+	}}
+	groups := NewGroupsSource(core.inner())
+
+	members, err := groups.ListMembers(ctx, "group1")
+	require.NoError(t, err)
+	core.assertInvoked(t, "GroupsListMembers", `{"group_id": "group1"}`)
+	assert.Equal(t, []GroupMember{{UserID: "user1", Name: "Jane", Email: "jane@example.com", Role: GroupRoleManager}}, members)
+
+	require.NoError(t, groups.AddMembers(ctx, "group1", []GroupMemberParams{{UserID: "user2", Role: GroupRoleMember}}))
+	core.assertInvoked(t, "GroupsAddMembers", `{"group_id": "group1", "members": [{"userId": "user2", "role": "member"}]}`)
+
+	require.NoError(t, groups.RemoveMembers(ctx, "group1", []string{"user2"}))
+	core.assertInvoked(t, "GroupsRemoveMembers", `{"group_id": "group1", "user_ids": ["user2"]}`)
+}
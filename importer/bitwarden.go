@@ -0,0 +1,222 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/1password/onepassword-sdk-go"
+)
+
+// Bitwarden item types
+const (
+	bitwardenLogin      = 1
+	bitwardenSecureNote = 2
+	bitwardenCard       = 3
+	bitwardenIdentity   = 4
+	bitwardenSSHKey     = 5
+)
+
+// Bitwarden custom field types
+const (
+	bitwardenFieldHidden = 1
+	bitwardenFieldLinked = 3
+)
+
+type bitwardenExport struct {
+	Encrypted bool `json:"encrypted"`
+	Folders   []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"folders"`
+	Items []bitwardenItem `json:"items"`
+}
+
+type bitwardenItem struct {
+	Type     int     `json:"type"`
+	Name     string  `json:"name"`
+	Notes    *string `json:"notes"`
+	FolderID *string `json:"folderId"`
+	Favorite bool    `json:"favorite"`
+	Fields   []struct {
+		Name  string  `json:"name"`
+		Value *string `json:"value"`
+		Type  int     `json:"type"`
+	} `json:"fields"`
+	Login *struct {
+		Username *string `json:"username"`
+		Password *string `json:"password"`
+		TOTP     *string `json:"totp"`
+		URIs     []struct {
+			URI *string `json:"uri"`
+		} `json:"uris"`
+	} `json:"login"`
+	Card *struct {
+		CardholderName *string `json:"cardholderName"`
+		Brand          *string `json:"brand"`
+		Number         *string `json:"number"`
+		ExpMonth       *string `json:"expMonth"`
+		ExpYear        *string `json:"expYear"`
+		Code           *string `json:"code"`
+	} `json:"card"`
+	Identity map[string]*string `json:"identity"`
+	SSHKey   *struct {
+		PrivateKey *string `json:"privateKey"`
+	} `json:"sshKey"`
+}
+
+// bitwardenIdentityFields maps the properties of Bitwarden identities to the titles of 1Password identity fields.
+var bitwardenIdentityFields = []struct {
+	key   string
+	title string
+}{
+	{"title", "title"},
+	{"firstName", "first name"},
+	{"middleName", "middle name"},
+	{"lastName", "last name"},
+	{"username", "username"},
+	{"company", "company"},
+	{"email", "email"},
+	{"phone", "phone"},
+	{"ssn", "social security number"},
+	{"passportNumber", "passport number"},
+	{"licenseNumber", "license number"},
+}
+
+// ParseBitwarden parses an unencrypted Bitwarden JSON export. Folders become tags.
+func ParseBitwarden(r io.Reader) (*Result, error) {
+	var export bitwardenExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("error parsing Bitwarden export: %w", err)
+	}
+	if export.Encrypted {
+		return nil, fmt.Errorf("encrypted Bitwarden exports are not supported, export your vault as unencrypted JSON")
+	}
+
+	folders := map[string]string{}
+	for _, folder := range export.Folders {
+		folders[folder.ID] = folder.Name
+	}
+
+	result := &Result{}
+	for _, item := range export.Items {
+		index := len(result.Items)
+		var b *itemBuilder
+		switch item.Type {
+		case bitwardenLogin:
+			b = newItemBuilder(onepassword.ItemCategoryLogin, item.Name)
+			if item.Login != nil {
+				b.builtIn("username", "username", onepassword.ItemFieldTypeText, str(item.Login.Username))
+				b.builtIn("password", "password", onepassword.ItemFieldTypeConcealed, str(item.Login.Password))
+				b.field("", "one-time password", onepassword.ItemFieldTypeTOTP, str(item.Login.TOTP))
+				for _, uri := range item.Login.URIs {
+					b.website(str(uri.URI), "")
+				}
+			}
+		case bitwardenSecureNote:
+			b = newItemBuilder(onepassword.ItemCategorySecureNote, item.Name)
+		case bitwardenCard:
+			b = newItemBuilder(onepassword.ItemCategoryCreditCard, item.Name)
+			if item.Card != nil {
+				b.field("", "cardholder name", onepassword.ItemFieldTypeText, str(item.Card.CardholderName))
+				b.field("", "type", onepassword.ItemFieldTypeCreditCardType, str(item.Card.Brand))
+				b.field("", "number", onepassword.ItemFieldTypeCreditCardNumber, str(item.Card.Number))
+				b.field("", "verification number", onepassword.ItemFieldTypeConcealed, str(item.Card.Code))
+				if expiry, ok := bitwardenExpiry(str(item.Card.ExpMonth), str(item.Card.ExpYear)); ok {
+					b.field("", "expiry date", onepassword.ItemFieldTypeMonthYear, expiry)
+				} else if str(item.Card.ExpMonth) != "" || str(item.Card.ExpYear) != "" {
+					result.Issues = append(result.Issues, Issue{Index: index, Title: item.Name, Message: "the card expiry date couldn't be parsed and was not imported"})
+				}
+			}
+		case bitwardenIdentity:
+			b = newItemBuilder(onepassword.ItemCategoryIdentity, item.Name)
+			for _, field := range bitwardenIdentityFields {
+				fieldType := onepassword.ItemFieldTypeText
+				switch field.key {
+				case "email":
+					fieldType = onepassword.ItemFieldTypeEmail
+				case "phone":
+					fieldType = onepassword.ItemFieldTypePhone
+				}
+				b.field("", field.title, fieldType, str(item.Identity[field.key]))
+			}
+			address := onepassword.AddressFieldDetails{
+				Street:  joinNonEmpty(", ", str(item.Identity["address1"]), str(item.Identity["address2"]), str(item.Identity["address3"])),
+				City:    str(item.Identity["city"]),
+				State:   str(item.Identity["state"]),
+				Zip:     str(item.Identity["postalCode"]),
+				Country: str(item.Identity["country"]),
+			}
+			if address != (onepassword.AddressFieldDetails{}) {
+				details := onepassword.NewItemFieldDetailsTypeVariantAddress(&address)
+				b.field("", "address", onepassword.ItemFieldTypeAddress, "").Details = &details
+			}
+		case bitwardenSSHKey:
+			b = newItemBuilder(onepassword.ItemCategorySSHKey, item.Name)
+			if item.SSHKey != nil {
+				b.builtIn("private_key", "private key", onepassword.ItemFieldTypeSSHKey, str(item.SSHKey.PrivateKey))
+			}
+		default:
+			b = newItemBuilder(onepassword.ItemCategorySecureNote, item.Name)
+			result.Issues = append(result.Issues, Issue{Index: index, Title: item.Name, Message: fmt.Sprintf("unknown Bitwarden item type %d, imported as a secure note", item.Type)})
+		}
+
+		b.notes(str(item.Notes))
+		for _, field := range item.Fields {
+			switch field.Type {
+			case bitwardenFieldHidden:
+				b.field("", field.Name, onepassword.ItemFieldTypeConcealed, str(field.Value))
+			case bitwardenFieldLinked:
+				result.Issues = append(result.Issues, Issue{Index: index, Title: item.Name, Message: fmt.Sprintf("linked field %q is not supported and was not imported", field.Name)})
+			default:
+				b.field("", field.Name, onepassword.ItemFieldTypeText, str(field.Value))
+			}
+		}
+		if item.FolderID != nil {
+			b.tag(folders[*item.FolderID])
+		}
+		if item.Favorite {
+			result.Issues = append(result.Issues, Issue{Index: index, Title: item.Name, Message: "favorites are not supported by the SDK, the item is imported without it"})
+		}
+		result.Items = append(result.Items, b.params)
+	}
+	return result, nil
+}
+
+// bitwardenExpiry converts a Bitwarden card expiry month and year to the MM/YYYY format of MonthYear fields.
+func bitwardenExpiry(month string, year string) (string, bool) {
+	m, err := strconv.Atoi(month)
+	if err != nil || m < 1 || m > 12 {
+		return "", false
+	}
+	y, err := strconv.Atoi(year)
+	if err != nil {
+		return "", false
+	}
+	if y < 100 {
+		y += 2000
+	}
+	return fmt.Sprintf("%02d/%04d", m, y), true
+}
+
+func str(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func joinNonEmpty(sep string, elems ...string) string {
+	var joined string
+	for _, e := range elems {
+		if e == "" {
+			continue
+		}
+		if joined != "" {
+			joined += sep
+		}
+		joined += e
+	}
+	return joined
+}
@@ -0,0 +1,147 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/1password/onepassword-sdk-go"
+)
+
+// The item properties a CSV column can be mapped to. Any other target is used as the title of a custom field.
+const (
+	ColumnTitle    = "title"
+	ColumnUsername = "username"
+	ColumnPassword = "password"
+	ColumnURL      = "url"
+	ColumnNotes    = "notes"
+	// A comma separated list of tags
+	ColumnTags = "tags"
+	// An otpauth:// URI or TOTP secret
+	ColumnOTP = "otp"
+	// The column is ignored
+	ColumnSkip = "-"
+)
+
+// defaultColumns maps common CSV headers, compared case-insensitively, to item properties.
+var defaultColumns = map[string]string{
+	"title":     ColumnTitle,
+	"name":      ColumnTitle,
+	"username":  ColumnUsername,
+	"user":      ColumnUsername,
+	"login":     ColumnUsername,
+	"email":     ColumnUsername,
+	"password":  ColumnPassword,
+	"url":       ColumnURL,
+	"website":   ColumnURL,
+	"login_uri": ColumnURL,
+	"notes":     ColumnNotes,
+	"note":      ColumnNotes,
+	"extra":     ColumnNotes,
+	"tags":      ColumnTags,
+	"otp":       ColumnOTP,
+	"totp":      ColumnOTP,
+}
+
+// CSVOptions configure ParseCSV.
+type CSVOptions struct {
+	// Maps column headers to item properties, see ColumnTitle and the other Column constants.
+	// Headers that aren't mapped are matched against common names like "name", "login" or "website",
+	// and otherwise become custom text fields titled after the header.
+	Columns map[string]string
+	// The category of the imported items. Defaults to Login.
+	Category onepassword.ItemCategory
+	// Ignore columns that aren't mapped to an item property, instead of importing them as custom fields.
+	IgnoreUnmapped bool
+	// The field delimiter. Defaults to a comma.
+	Comma rune
+}
+
+// ParseCSV parses a CSV file with a header row, creating one item per row.
+func ParseCSV(r io.Reader, opts CSVOptions) (*Result, error) {
+	reader := csv.NewReader(r)
+	if opts.Comma != 0 {
+		reader.Comma = opts.Comma
+	}
+	reader.FieldsPerRecord = -1
+	category := opts.Category
+	if category == "" {
+		category = onepassword.ItemCategoryLogin
+	}
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return &Result{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading CSV header: %w", err)
+	}
+
+	targets := make([]string, len(header))
+	hasTitle := false
+	for i, column := range header {
+		column = strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))
+		target, ok := opts.Columns[column]
+		if !ok {
+			target, ok = defaultColumns[strings.ToLower(column)]
+		}
+		if !ok {
+			target = column
+			if opts.IgnoreUnmapped {
+				target = ColumnSkip
+			}
+		}
+		targets[i] = target
+		hasTitle = hasTitle || target == ColumnTitle
+	}
+
+	result := &Result{}
+	if !hasTitle {
+		result.Issues = append(result.Issues, Issue{Index: -1, Message: "no column is mapped to the item title, items are titled after their row number"})
+	}
+
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading CSV row %d: %w", row, err)
+		}
+
+		b := newItemBuilder(category, fmt.Sprintf("Imported item %d", row-1))
+		// Read the title first, so that issues about the row are reported with it.
+		for i, value := range record {
+			if i < len(targets) && targets[i] == ColumnTitle && value != "" {
+				b.params.Title = value
+			}
+		}
+		for i, value := range record {
+			if i >= len(targets) {
+				result.Issues = append(result.Issues, Issue{Index: len(result.Items), Title: b.params.Title, Message: fmt.Sprintf("row %d has more columns than the header, the extra values are ignored", row)})
+				break
+			}
+			switch targets[i] {
+			case ColumnSkip, ColumnTitle:
+			case ColumnUsername:
+				b.builtIn("username", "username", onepassword.ItemFieldTypeText, value)
+			case ColumnPassword:
+				b.builtIn("password", "password", onepassword.ItemFieldTypeConcealed, value)
+			case ColumnURL:
+				b.website(value, "")
+			case ColumnNotes:
+				b.notes(value)
+			case ColumnTags:
+				b.tag(strings.Split(value, ",")...)
+			case ColumnOTP:
+				b.field("", "one-time password", onepassword.ItemFieldTypeTOTP, value)
+			default:
+				b.field("", targets[i], onepassword.ItemFieldTypeText, value)
+			}
+		}
+		result.Items = append(result.Items, b.params)
+	}
+	return result, nil
+}
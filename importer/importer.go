@@ -0,0 +1,272 @@
+// Package importer converts exports of other password managers into 1Password items and creates them in a vault.
+//
+// The supported formats are 1Password's own 1PUX export, CSV files with a configurable column mapping,
+// Bitwarden's unencrypted JSON export and KeePass 2.x XML exports. Each parser returns a Result holding the
+// items to create and any issues found while mapping them, which can be reviewed before calling Import.
+package importer
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/1password/onepassword-sdk-go"
+)
+
+// Result holds the items parsed from an export.
+type Result struct {
+	// The items to create. Their VaultID is set by Import.
+	Items []onepassword.ItemCreateParams
+	// Issues found while mapping the export to 1Password items
+	Issues []Issue
+}
+
+// Issue describes data of an export that couldn't be mapped exactly.
+type Issue struct {
+	// The index of the affected item in Result.Items, or -1 if the issue isn't specific to an item
+	Index int
+	// The title of the affected item
+	Title string
+	// A description of the issue
+	Message string
+}
+
+func (i Issue) String() string {
+	if i.Index < 0 {
+		return i.Message
+	}
+	return fmt.Sprintf("item %d (%q): %s", i.Index, i.Title, i.Message)
+}
+
+// Options configure Import.
+type Options struct {
+	// Only report what would be imported, without creating any items.
+	DryRun bool
+	// Don't create items with the same title and category as an existing item in the vault or an earlier
+	// item of the import.
+	SkipDuplicates bool
+	// Record the SHA-256 hashes of the imported files in the created items, so that they're verified when
+	// they're read with onepassword.ReadFileVerified or by a client created with onepassword.WithFileIntegrity.
+	RecordFileHashes bool
+}
+
+// Duplicate is an imported item that has the same title and category as an existing item, or as an earlier
+// item of the import.
+type Duplicate struct {
+	// The index of the item in Result.Items
+	Index int
+	// The title of the item
+	Title string
+	// The ID of the existing item in the vault, empty if the item duplicates an earlier imported item
+	ExistingItemID string
+	// The index in Result.Items of the earlier imported item it duplicates, or -1 if it duplicates an existing item
+	DuplicateOf int
+}
+
+// Failure is an item that couldn't be created.
+type Failure struct {
+	// The index of the item in Result.Items
+	Index int
+	// The title of the item
+	Title string
+	// The reason the item couldn't be created
+	Reason onepassword.ItemUpdateFailureReason
+}
+
+// Report describes the outcome of an import.
+type Report struct {
+	// The items created, empty for a dry run
+	Created []onepassword.Item
+	// Items matching an existing item or an earlier imported item. They are skipped if Options.SkipDuplicates is set.
+	Duplicates []Duplicate
+	// Items the server refused to create
+	Failed []Failure
+	// The mapping issues of the parsed export
+	Issues []Issue
+}
+
+// Import creates the parsed items in the given vault, reporting duplicates of items that already exist in it and
+// items that appear more than once in the export.
+// If creating the items fails partway, the report is returned together with the error, so the items that were
+// created are known.
+func Import(ctx context.Context, client *onepassword.Client, vaultID string, result *Result, opts Options) (*Report, error) {
+	report := &Report{Issues: result.Issues}
+
+	existing, err := client.Items().List(ctx, vaultID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing items of vault %s: %w", vaultID, err)
+	}
+	existingIDs := map[duplicateKey]string{}
+	for _, overview := range existing {
+		existingIDs[duplicateKey{title: overview.Title, category: overview.Category}] = overview.ID
+	}
+
+	var toCreate []onepassword.ItemCreateParams
+	var indices []int
+	imported := map[duplicateKey]int{}
+	for i, params := range result.Items {
+		key := duplicateKey{title: params.Title, category: params.Category}
+		duplicate := true
+		if id, ok := existingIDs[key]; ok {
+			report.Duplicates = append(report.Duplicates, Duplicate{Index: i, Title: params.Title, ExistingItemID: id, DuplicateOf: -1})
+		} else if first, ok := imported[key]; ok {
+			report.Duplicates = append(report.Duplicates, Duplicate{Index: i, Title: params.Title, DuplicateOf: first})
+		} else {
+			imported[key] = i
+			duplicate = false
+		}
+		if duplicate && opts.SkipDuplicates {
+			continue
+		}
+		params.VaultID = vaultID
+		if opts.RecordFileHashes {
+			params = onepassword.WithFileHashes(params)
+		}
+		toCreate = append(toCreate, params)
+		indices = append(indices, i)
+	}
+
+	if opts.DryRun || len(toCreate) == 0 {
+		return report, nil
+	}
+
+	// If a batch fails, the items of the completed batches are still reported, so the import can be resumed.
+	response, err := client.Items().CreateAll(ctx, vaultID, toCreate)
+	if err != nil && len(response.IndividualResponses) != len(toCreate) {
+		return nil, err
+	}
+	for i, res := range response.IndividualResponses {
+		if res.Error != nil {
+			report.Failed = append(report.Failed, Failure{Index: indices[i], Title: toCreate[i].Title, Reason: *res.Error})
+			continue
+		}
+		if res.Content != nil {
+			report.Created = append(report.Created, *res.Content)
+		}
+	}
+	if err != nil {
+		return report, fmt.Errorf("error creating items in vault %s: %w", vaultID, err)
+	}
+	return report, nil
+}
+
+type duplicateKey struct {
+	title    string
+	category onepassword.ItemCategory
+}
+
+// itemBuilder assembles ItemCreateParams while generating unique section and field IDs.
+type itemBuilder struct {
+	params   onepassword.ItemCreateParams
+	sections map[string]string
+	ids      map[string]bool
+}
+
+func newItemBuilder(category onepassword.ItemCategory, title string) *itemBuilder {
+	return &itemBuilder{
+		params:   onepassword.ItemCreateParams{Category: category, Title: title},
+		sections: map[string]string{},
+		ids:      map[string]bool{},
+	}
+}
+
+// uniqueID returns a slug of base that isn't used by any other section or field of the item yet.
+func (b *itemBuilder) uniqueID(base string) string {
+	id := slug(base)
+	if id == "" {
+		id = "field"
+	}
+	candidate := id
+	for n := 2; b.ids[candidate]; n++ {
+		candidate = id + strconv.Itoa(n)
+	}
+	b.ids[candidate] = true
+	return candidate
+}
+
+// builtIn adds a field outside of any section, such as the username and password of a login, using id as its ID.
+func (b *itemBuilder) builtIn(id string, title string, fieldType onepassword.ItemFieldType, value string) {
+	if value == "" {
+		return
+	}
+	b.ids[id] = true
+	b.params.Fields = append(b.params.Fields, onepassword.ItemField{ID: id, Title: title, FieldType: fieldType, Value: value})
+}
+
+// section returns the ID of the section with the given title, creating the section if needed.
+func (b *itemBuilder) section(title string) string {
+	if sectionID, ok := b.sections[title]; ok {
+		return sectionID
+	}
+	base := title
+	if base == "" {
+		base = "add more"
+	}
+	sectionID := b.uniqueID(base)
+	b.sections[title] = sectionID
+	b.params.Sections = append(b.params.Sections, onepassword.ItemSection{ID: sectionID, Title: title})
+	return sectionID
+}
+
+// field adds a field to the section with the given title, creating the section if needed.
+// The returned pointer is only valid until the next field is added.
+func (b *itemBuilder) field(section string, title string, fieldType onepassword.ItemFieldType, value string) *onepassword.ItemField {
+	if value == "" && fieldType != onepassword.ItemFieldTypeAddress {
+		return nil
+	}
+	sectionID := b.section(section)
+	b.params.Fields = append(b.params.Fields, onepassword.ItemField{
+		ID:        b.uniqueID(title),
+		Title:     title,
+		SectionID: &sectionID,
+		FieldType: fieldType,
+		Value:     value,
+	})
+	return &b.params.Fields[len(b.params.Fields)-1]
+}
+
+func (b *itemBuilder) website(url string, label string) {
+	if url == "" {
+		return
+	}
+	if label == "" {
+		label = "website"
+	}
+	b.params.Websites = append(b.params.Websites, onepassword.Website{
+		URL:              url,
+		Label:            label,
+		AutofillBehavior: onepassword.AutofillBehaviorAnywhereOnWebsite,
+	})
+}
+
+func (b *itemBuilder) notes(notes string) {
+	if notes == "" {
+		return
+	}
+	if b.params.Notes != nil {
+		notes = *b.params.Notes + "\n\n" + notes
+	}
+	b.params.Notes = &notes
+}
+
+func (b *itemBuilder) tag(tags ...string) {
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			b.params.Tags = append(b.params.Tags, tag)
+		}
+	}
+}
+
+func slug(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == ' ', r == '-', r == '_':
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,263 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/1password/onepassword-sdk-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCSV(t *testing.T) {
+	input := "\ufeffName,Login,Password,Website,PIN\n" +
+		"GitHub,octocat,hunter2,https://github.com,1234\n" +
+		",anonymous,,,\n"
+
+	result, err := ParseCSV(strings.NewReader(input), CSVOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.Items, 2)
+	assert.Empty(t, result.Issues)
+
+	github := result.Items[0]
+	assert.Equal(t, "GitHub", github.Title)
+	assert.Equal(t, onepassword.ItemCategoryLogin, github.Category)
+	require.Len(t, github.Fields, 3)
+	assert.Equal(t, "username", github.Fields[0].ID)
+	assert.Equal(t, "octocat", github.Fields[0].Value)
+	assert.Equal(t, onepassword.ItemFieldTypeConcealed, github.Fields[1].FieldType)
+	assert.Equal(t, "PIN", github.Fields[2].Title)
+	assert.Equal(t, "add_more", *github.Fields[2].SectionID)
+	require.Len(t, github.Websites, 1)
+	assert.Equal(t, "https://github.com", github.Websites[0].URL)
+
+	assert.Equal(t, "Imported item 2", result.Items[1].Title)
+}
+
+func TestParseCSVIssuesCarryTitle(t *testing.T) {
+	input := "Login,Name\n" +
+		"octocat,GitHub,extra\n"
+
+	result, err := ParseCSV(strings.NewReader(input), CSVOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.Issues, 1)
+	assert.Equal(t, "GitHub", result.Issues[0].Title)
+	assert.Contains(t, result.Issues[0].Message, "row 2 has more columns than the header")
+}
+
+func TestParseBitwarden(t *testing.T) {
+	input := `{
+		"encrypted": false,
+		"folders": [{"id": "f1", "name": "Work"}],
+		"items": [{
+			"type": 3,
+			"name": "Visa",
+			"folderId": "f1",
+			"favorite": true,
+			"fields": [{"name": "PIN", "value": "0000", "type": 1}],
+			"card": {"cardholderName": "Jane Doe", "number": "4111111111111111", "expMonth": "7", "expYear": "29"}
+		}]
+	}`
+
+	result, err := ParseBitwarden(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, result.Items, 1)
+
+	card := result.Items[0]
+	assert.Equal(t, onepassword.ItemCategoryCreditCard, card.Category)
+	assert.Equal(t, []string{"Work"}, card.Tags)
+
+	values := map[string]onepassword.ItemField{}
+	for _, field := range card.Fields {
+		values[field.Title] = field
+	}
+	assert.Equal(t, "07/2029", values["expiry date"].Value)
+	assert.Equal(t, onepassword.ItemFieldTypeCreditCardNumber, values["number"].FieldType)
+	assert.Equal(t, onepassword.ItemFieldTypeConcealed, values["PIN"].FieldType)
+
+	require.Len(t, result.Issues, 1)
+	assert.Equal(t, 0, result.Issues[0].Index)
+}
+
+func TestParse1PUX(t *testing.T) {
+	exportData := `{"accounts": [{"vaults": [{
+		"attrs": {"name": "Personal"},
+		"items": [
+			{
+				"categoryUuid": "001",
+				"overview": {"title": "GitHub", "tags": ["dev"], "urls": [{"label": "", "url": "https://github.com"}]},
+				"details": {
+					"loginFields": [
+						{"value": "octocat", "name": "login", "fieldType": "T", "designation": "username"},
+						{"value": "hunter2", "name": "password", "fieldType": "P", "designation": "password"}
+					],
+					"sections": [{"title": "Recovery", "fields": [
+						{"title": "expires", "value": {"monthYear": 202907}},
+						{"title": "issued", "value": {"date": 1700000000}},
+						{"title": "contact", "value": {"email": {"email_address": "jane@example.com"}}},
+						{"title": "codes", "value": {"file": {"fileName": "codes.txt", "documentId": "doc1"}}},
+						{"title": "missing", "value": {"file": {"fileName": "gone.txt", "documentId": "doc9"}}}
+					]}]
+				}
+			},
+			{
+				"categoryUuid": "006",
+				"overview": {"title": "Passport scan"},
+				"details": {"documentAttributes": {"fileName": "scan.pdf", "documentId": "doc2"}}
+			},
+			{"categoryUuid": "999", "overview": {"title": "Mystery"}, "details": {"notesPlain": "?"}},
+			{"state": "archived", "categoryUuid": "001", "overview": {"title": "Old"}}
+		]
+	}]}]}`
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"export.data":           exportData,
+		"files/doc1__codes.txt": "1234-5678",
+		"files/doc2__scan.pdf":  "%PDF",
+	} {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	result, err := Parse1PUX(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	require.Len(t, result.Items, 3, "archived items are skipped")
+
+	login := result.Items[0]
+	assert.Equal(t, onepassword.ItemCategoryLogin, login.Category)
+	assert.Equal(t, []string{"dev", "Personal"}, login.Tags)
+	values := map[string]onepassword.ItemField{}
+	for _, field := range login.Fields {
+		values[field.Title] = field
+	}
+	assert.Equal(t, "octocat", values["username"].Value)
+	assert.Equal(t, onepassword.ItemFieldTypeConcealed, values["password"].FieldType)
+	assert.Equal(t, "07/2029", values["expires"].Value)
+	assert.Equal(t, onepassword.ItemFieldTypeMonthYear, values["expires"].FieldType)
+	assert.Equal(t, "2023-11-14", values["issued"].Value)
+	assert.Equal(t, "jane@example.com", values["contact"].Value)
+	require.Len(t, login.Files, 1)
+	assert.Equal(t, "codes.txt", login.Files[0].Name)
+	assert.Equal(t, []byte("1234-5678"), login.Files[0].Content)
+	assert.Equal(t, *values["expires"].SectionID, login.Files[0].SectionID)
+
+	document := result.Items[1]
+	assert.Equal(t, onepassword.ItemCategoryDocument, document.Category)
+	require.NotNil(t, document.Document)
+	assert.Equal(t, []byte("%PDF"), document.Document.Content)
+
+	assert.Equal(t, onepassword.ItemCategorySecureNote, result.Items[2].Category)
+
+	require.Len(t, result.Issues, 2)
+	assert.Equal(t, 0, result.Issues[0].Index)
+	assert.Contains(t, result.Issues[0].Message, `file "gone.txt" couldn't be read`)
+	assert.Equal(t, 2, result.Issues[1].Index)
+	assert.Contains(t, result.Issues[1].Message, `unknown category "999"`)
+}
+
+func TestParseKeePassXML(t *testing.T) {
+	input := `<?xml version="1.0" encoding="utf-8"?>
+<KeePassFile>
+	<Root>
+		<Group>
+			<Name>Database</Name>
+			<Entry>
+				<String><Key>Title</Key><Value>Router</Value></String>
+				<String><Key>Password</Key><Value Protected="True">admin</Value></String>
+			</Entry>
+			<Group>
+				<Name>Servers</Name>
+				<Group>
+					<Name>Production</Name>
+					<Entry>
+						<String><Key>Title</Key><Value>db1</Value></String>
+						<String><Key>UserName</Key><Value>root</Value></String>
+						<String><Key>URL</Key><Value>ssh://db1.internal</Value></String>
+						<String><Key>API token</Key><Value Protected="True">s3cret</Value></String>
+						<String><Key>Rack</Key><Value>B2</Value></String>
+						<String><Key>otp</Key><Value>otpauth://totp/db1?secret=JBSWY3DPEHPK3PXP</Value></String>
+						<Binary><Key>id_rsa</Key><Value Ref="0"/></Binary>
+						<Tags>linux;critical</Tags>
+					</Entry>
+					<Entry>
+						<String><Key>UserName</Key><Value>nobody</Value></String>
+					</Entry>
+				</Group>
+			</Group>
+		</Group>
+	</Root>
+</KeePassFile>`
+
+	result, err := ParseKeePassXML(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, result.Items, 3)
+
+	assert.Equal(t, "Router", result.Items[0].Title)
+	assert.Empty(t, result.Items[0].Tags, "entries of the root group have no group path")
+
+	db := result.Items[1]
+	assert.Equal(t, []string{"Servers/Production", "linux", "critical"}, db.Tags)
+	require.Len(t, db.Websites, 1)
+	assert.Equal(t, "ssh://db1.internal", db.Websites[0].URL)
+	types := map[string]onepassword.ItemFieldType{}
+	for _, field := range db.Fields {
+		types[field.Title] = field.FieldType
+	}
+	assert.Equal(t, onepassword.ItemFieldTypeText, types["username"])
+	assert.Equal(t, onepassword.ItemFieldTypeConcealed, types["API token"])
+	assert.Equal(t, onepassword.ItemFieldTypeText, types["Rack"])
+	assert.Equal(t, onepassword.ItemFieldTypeTOTP, types["one-time password"])
+
+	assert.Equal(t, "Untitled", result.Items[2].Title)
+	require.Len(t, result.Issues, 2)
+	assert.Equal(t, Issue{Index: 1, Title: "db1", Message: `attachment "id_rsa" is stored outside of the XML export and was not imported`}, result.Issues[0])
+	assert.Equal(t, 2, result.Issues[1].Index)
+}
+
+type fakeItems struct {
+	onepassword.ItemsAPI
+	existing []onepassword.ItemOverview
+	created  []onepassword.ItemCreateParams
+}
+
+func (f *fakeItems) List(ctx context.Context, vaultID string, filters ...onepassword.ItemListFilter) ([]onepassword.ItemOverview, error) {
+	return f.existing, nil
+}
+
+func (f *fakeItems) CreateAll(ctx context.Context, vaultID string, params []onepassword.ItemCreateParams) (onepassword.ItemsUpdateAllResponse, error) {
+	f.created = append(f.created, params...)
+	var response onepassword.ItemsUpdateAllResponse
+	for _, p := range params {
+		item := onepassword.Item{Title: p.Title, Category: p.Category}
+		response.IndividualResponses = append(response.IndividualResponses, onepassword.Response[onepassword.Item, onepassword.ItemUpdateFailureReason]{Content: &item})
+	}
+	return response, nil
+}
+
+func TestImportDuplicates(t *testing.T) {
+	items := &fakeItems{existing: []onepassword.ItemOverview{{ID: "existing1", Title: "GitHub", Category: onepassword.ItemCategoryLogin}}}
+	client := &onepassword.Client{ItemsAPI: items}
+	result := &Result{Items: []onepassword.ItemCreateParams{
+		{Title: "GitHub", Category: onepassword.ItemCategoryLogin},
+		{Title: "AWS", Category: onepassword.ItemCategoryLogin},
+		{Title: "AWS", Category: onepassword.ItemCategoryLogin},
+		{Title: "AWS", Category: onepassword.ItemCategorySecureNote},
+	}}
+
+	report, err := Import(context.Background(), client, "vault1", result, Options{SkipDuplicates: true})
+	require.NoError(t, err)
+	assert.Equal(t, []Duplicate{
+		{Index: 0, Title: "GitHub", ExistingItemID: "existing1", DuplicateOf: -1},
+		{Index: 2, Title: "AWS", DuplicateOf: 1},
+	}, report.Duplicates)
+	require.Len(t, items.created, 2)
+	assert.Equal(t, onepassword.ItemCategorySecureNote, items.created[1].Category)
+}
@@ -0,0 +1,105 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/1password/onepassword-sdk-go"
+)
+
+type keePassFile struct {
+	Root struct {
+		Groups []keePassGroup `xml:"Group"`
+	} `xml:"Root"`
+}
+
+type keePassGroup struct {
+	Name    string         `xml:"Name"`
+	Entries []keePassEntry `xml:"Entry"`
+	Groups  []keePassGroup `xml:"Group"`
+}
+
+type keePassEntry struct {
+	Strings []struct {
+		Key   string `xml:"Key"`
+		Value struct {
+			Value     string `xml:",chardata"`
+			Protected string `xml:"Protected,attr"`
+		} `xml:"Value"`
+	} `xml:"String"`
+	Binaries []struct {
+		Key string `xml:"Key"`
+	} `xml:"Binary"`
+	Tags string `xml:"Tags"`
+}
+
+// ParseKeePassXML parses an unencrypted KeePass 2.x XML export. The group path of each entry becomes a tag,
+// e.g. "Servers/Production", and custom strings marked as protected become concealed fields.
+func ParseKeePassXML(r io.Reader) (*Result, error) {
+	var file keePassFile
+	if err := xml.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("error parsing KeePass XML export: %w", err)
+	}
+
+	result := &Result{}
+	for _, root := range file.Root.Groups {
+		// The root group holds the database itself, its name isn't part of the group path.
+		parseKeePassGroup(root, "", result)
+	}
+	return result, nil
+}
+
+func parseKeePassGroup(group keePassGroup, path string, result *Result) {
+	for _, entry := range group.Entries {
+		parseKeePassEntry(entry, path, result)
+	}
+	for _, child := range group.Groups {
+		childPath := child.Name
+		if path != "" {
+			childPath = path + "/" + child.Name
+		}
+		parseKeePassGroup(child, childPath, result)
+	}
+}
+
+func parseKeePassEntry(entry keePassEntry, path string, result *Result) {
+	index := len(result.Items)
+	b := newItemBuilder(onepassword.ItemCategoryLogin, "")
+	for _, s := range entry.Strings {
+		value := s.Value.Value
+		switch s.Key {
+		case "Title":
+			b.params.Title = value
+		case "UserName":
+			b.builtIn("username", "username", onepassword.ItemFieldTypeText, value)
+		case "Password":
+			b.builtIn("password", "password", onepassword.ItemFieldTypeConcealed, value)
+		case "URL":
+			b.website(value, "")
+		case "Notes":
+			b.notes(value)
+		case "otp", "TimeOtp-Secret-Base32":
+			b.field("", "one-time password", onepassword.ItemFieldTypeTOTP, value)
+		default:
+			fieldType := onepassword.ItemFieldTypeText
+			if strings.EqualFold(s.Value.Protected, "true") {
+				fieldType = onepassword.ItemFieldTypeConcealed
+			}
+			b.field("", s.Key, fieldType, value)
+		}
+	}
+	if b.params.Title == "" {
+		b.params.Title = "Untitled"
+		result.Issues = append(result.Issues, Issue{Index: index, Title: b.params.Title, Message: "the entry has no title"})
+	}
+	if path != "" {
+		b.tag(path)
+	}
+	b.tag(strings.FieldsFunc(entry.Tags, func(r rune) bool { return r == ';' || r == ',' })...)
+	for _, binary := range entry.Binaries {
+		result.Issues = append(result.Issues, Issue{Index: index, Title: b.params.Title, Message: fmt.Sprintf("attachment %q is stored outside of the XML export and was not imported", binary.Key)})
+	}
+	result.Items = append(result.Items, b.params)
+}
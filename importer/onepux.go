@@ -0,0 +1,286 @@
+package importer
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/1password/onepassword-sdk-go"
+)
+
+// onePUXCategories maps the category UUIDs of 1PUX exports to item categories.
+var onePUXCategories = map[string]onepassword.ItemCategory{
+	"001": onepassword.ItemCategoryLogin,
+	"002": onepassword.ItemCategoryCreditCard,
+	"003": onepassword.ItemCategorySecureNote,
+	"004": onepassword.ItemCategoryIdentity,
+	"005": onepassword.ItemCategoryPassword,
+	"006": onepassword.ItemCategoryDocument,
+	"100": onepassword.ItemCategorySoftwareLicense,
+	"101": onepassword.ItemCategoryBankAccount,
+	"102": onepassword.ItemCategoryDatabase,
+	"103": onepassword.ItemCategoryDriverLicense,
+	"104": onepassword.ItemCategoryOutdoorLicense,
+	"105": onepassword.ItemCategoryMembership,
+	"106": onepassword.ItemCategoryPassport,
+	"107": onepassword.ItemCategoryRewards,
+	"108": onepassword.ItemCategorySocialSecurityNumber,
+	"109": onepassword.ItemCategoryRouter,
+	"110": onepassword.ItemCategoryServer,
+	"111": onepassword.ItemCategoryEmail,
+	"112": onepassword.ItemCategoryAPICredentials,
+	"113": onepassword.ItemCategoryMedicalRecord,
+	"114": onepassword.ItemCategorySSHKey,
+	"115": onepassword.ItemCategoryCryptoWallet,
+}
+
+type onePUXExport struct {
+	Accounts []struct {
+		Vaults []struct {
+			Attrs struct {
+				Name string `json:"name"`
+			} `json:"attrs"`
+			Items []onePUXItem `json:"items"`
+		} `json:"vaults"`
+	} `json:"accounts"`
+}
+
+type onePUXItem struct {
+	State        string `json:"state"`
+	CategoryUUID string `json:"categoryUuid"`
+	Overview     struct {
+		Title string   `json:"title"`
+		URL   string   `json:"url"`
+		Tags  []string `json:"tags"`
+		URLs  []struct {
+			Label string `json:"label"`
+			URL   string `json:"url"`
+		} `json:"urls"`
+	} `json:"overview"`
+	Details struct {
+		LoginFields []struct {
+			Value       string `json:"value"`
+			Name        string `json:"name"`
+			FieldType   string `json:"fieldType"`
+			Designation string `json:"designation"`
+		} `json:"loginFields"`
+		NotesPlain string `json:"notesPlain"`
+		Password   string `json:"password"`
+		Sections   []struct {
+			Title  string `json:"title"`
+			Fields []struct {
+				Title string                     `json:"title"`
+				Value map[string]json.RawMessage `json:"value"`
+			} `json:"fields"`
+		} `json:"sections"`
+		DocumentAttributes *onePUXFile `json:"documentAttributes"`
+	} `json:"details"`
+}
+
+type onePUXFile struct {
+	FileName   string `json:"fileName"`
+	DocumentID string `json:"documentId"`
+}
+
+// Parse1PUX parses a 1Password 1PUX export, including document and file attachment contents.
+// The vault name of each item becomes a tag, and archived items are skipped.
+func Parse1PUX(r io.ReaderAt, size int64) (*Result, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("error opening 1PUX export: %w", err)
+	}
+
+	data, err := archive.Open("export.data")
+	if err != nil {
+		return nil, fmt.Errorf("error opening 1PUX export: %w", err)
+	}
+	defer data.Close()
+	var export onePUXExport
+	if err := json.NewDecoder(data).Decode(&export); err != nil {
+		return nil, fmt.Errorf("error parsing 1PUX export: %w", err)
+	}
+
+	readFile := func(file onePUXFile) ([]byte, error) {
+		f, err := archive.Open("files/" + file.DocumentID + "__" + file.FileName)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return io.ReadAll(f)
+	}
+
+	result := &Result{}
+	for _, account := range export.Accounts {
+		for _, vault := range account.Vaults {
+			for _, item := range vault.Items {
+				if item.State == "archived" {
+					continue
+				}
+				parse1PUXItem(item, vault.Attrs.Name, readFile, result)
+			}
+		}
+	}
+	return result, nil
+}
+
+func parse1PUXItem(item onePUXItem, vaultName string, readFile func(onePUXFile) ([]byte, error), result *Result) {
+	index := len(result.Items)
+	title := item.Overview.Title
+	issue := func(format string, args ...any) {
+		result.Issues = append(result.Issues, Issue{Index: index, Title: title, Message: fmt.Sprintf(format, args...)})
+	}
+
+	category, ok := onePUXCategories[item.CategoryUUID]
+	if !ok {
+		category = onepassword.ItemCategorySecureNote
+		issue("unknown category %q, imported as a secure note", item.CategoryUUID)
+	}
+	b := newItemBuilder(category, title)
+
+	for _, field := range item.Details.LoginFields {
+		switch field.Designation {
+		case "username":
+			b.builtIn("username", "username", onepassword.ItemFieldTypeText, field.Value)
+		case "password":
+			b.builtIn("password", "password", onepassword.ItemFieldTypeConcealed, field.Value)
+		default:
+			fieldType := onepassword.ItemFieldTypeText
+			if field.FieldType == "P" {
+				fieldType = onepassword.ItemFieldTypeConcealed
+			}
+			b.field("", field.Name, fieldType, field.Value)
+		}
+	}
+	b.builtIn("password", "password", onepassword.ItemFieldTypeConcealed, item.Details.Password)
+	b.notes(item.Details.NotesPlain)
+
+	if len(item.Overview.URLs) == 0 {
+		b.website(item.Overview.URL, "")
+	}
+	for _, url := range item.Overview.URLs {
+		b.website(url.URL, url.Label)
+	}
+	b.tag(item.Overview.Tags...)
+	b.tag(vaultName)
+
+	for _, section := range item.Details.Sections {
+		for _, field := range section.Fields {
+			for kind, raw := range field.Value {
+				parse1PUXField(b, section.Title, field.Title, kind, raw, readFile, issue)
+			}
+		}
+	}
+
+	if doc := item.Details.DocumentAttributes; doc != nil {
+		content, err := readFile(*doc)
+		if err != nil {
+			issue("document %q couldn't be read from the export: %v", doc.FileName, err)
+		} else {
+			b.params.Document = &onepassword.DocumentCreateParams{Name: doc.FileName, Content: content}
+		}
+	}
+
+	result.Items = append(result.Items, b.params)
+}
+
+// parse1PUXField adds a section field of a 1PUX item, whose value is an object with a single key naming its type.
+func parse1PUXField(b *itemBuilder, section string, title string, kind string, raw json.RawMessage, readFile func(onePUXFile) ([]byte, error), issue func(string, ...any)) {
+	fieldTypes := map[string]onepassword.ItemFieldType{
+		"string":           onepassword.ItemFieldTypeText,
+		"concealed":        onepassword.ItemFieldTypeConcealed,
+		"url":              onepassword.ItemFieldTypeURL,
+		"phone":            onepassword.ItemFieldTypePhone,
+		"totp":             onepassword.ItemFieldTypeTOTP,
+		"menu":             onepassword.ItemFieldTypeMenu,
+		"creditCardType":   onepassword.ItemFieldTypeCreditCardType,
+		"creditCardNumber": onepassword.ItemFieldTypeCreditCardNumber,
+		"reference":        onepassword.ItemFieldTypeReference,
+		"gender":           onepassword.ItemFieldTypeText,
+	}
+
+	if fieldType, ok := fieldTypes[kind]; ok {
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			issue("field %q has an invalid %s value", title, kind)
+			return
+		}
+		b.field(section, title, fieldType, value)
+		return
+	}
+
+	switch kind {
+	case "email":
+		var email struct {
+			Address string `json:"email_address"`
+		}
+		if err := json.Unmarshal(raw, &email); err != nil {
+			issue("field %q has an invalid email value", title)
+			return
+		}
+		b.field(section, title, onepassword.ItemFieldTypeEmail, email.Address)
+	case "date":
+		var seconds int64
+		if err := json.Unmarshal(raw, &seconds); err != nil {
+			issue("field %q has an invalid date value", title)
+			return
+		}
+		b.field(section, title, onepassword.ItemFieldTypeDate, time.Unix(seconds, 0).UTC().Format("2006-01-02"))
+	case "monthYear":
+		var yearMonth int
+		if err := json.Unmarshal(raw, &yearMonth); err != nil {
+			issue("field %q has an invalid month-year value", title)
+			return
+		}
+		b.field(section, title, onepassword.ItemFieldTypeMonthYear, fmt.Sprintf("%02d/%04d", yearMonth%100, yearMonth/100))
+	case "address":
+		var address onepassword.AddressFieldDetails
+		if err := json.Unmarshal(raw, &address); err != nil {
+			issue("field %q has an invalid address value", title)
+			return
+		}
+		details := onepassword.NewItemFieldDetailsTypeVariantAddress(&address)
+		b.field(section, title, onepassword.ItemFieldTypeAddress, "").Details = &details
+	case "sshKey":
+		var key struct {
+			PrivateKey string `json:"privateKey"`
+		}
+		if err := json.Unmarshal(raw, &key); err != nil {
+			issue("field %q has an invalid SSH key value", title)
+			return
+		}
+		b.builtIn("private_key", title, onepassword.ItemFieldTypeSSHKey, key.PrivateKey)
+	case "file":
+		var file onePUXFile
+		if err := json.Unmarshal(raw, &file); err != nil {
+			issue("field %q has an invalid file value", title)
+			return
+		}
+		content, err := readFile(file)
+		if err != nil {
+			issue("file %q couldn't be read from the export: %v", file.FileName, err)
+			return
+		}
+		b.params.Files = append(b.params.Files, onepassword.FileCreateParams{
+			Name:      file.FileName,
+			Content:   content,
+			SectionID: b.section(section),
+			FieldID:   b.uniqueID(title),
+		})
+	default:
+		var value any
+		_ = json.Unmarshal(raw, &value)
+		switch v := value.(type) {
+		case string:
+			b.field(section, title, onepassword.ItemFieldTypeText, v)
+			issue("field %q has unsupported type %q and was imported as text", title, kind)
+		case float64:
+			b.field(section, title, onepassword.ItemFieldTypeText, strconv.FormatFloat(v, 'f', -1, 64))
+			issue("field %q has unsupported type %q and was imported as text", title, kind)
+		default:
+			issue("field %q has unsupported type %q and was not imported", title, kind)
+		}
+	}
+}
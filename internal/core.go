@@ -53,8 +53,8 @@ func (c *CoreWrapper) Invoke(ctx context.Context, invokeConfig InvokeConfig) (*s
 	if err != nil {
 		return nil, err
 	}
-	if len(input) > messageLimit {
-		return nil, fmt.Errorf("message size exceeds the limit of %d bytes, please contact 1Password at support@1password.com or https://developer.1password.com/joinslack if you need help", messageLimit)
+	if len(input) > MessageLimit {
+		return nil, fmt.Errorf("message size exceeds the limit of %d bytes, please contact 1Password at support@1password.com or https://developer.1password.com/joinslack if you need help", MessageLimit)
 	}
 	res, err := c.InnerCore.Invoke(ctx, input)
 	if err != nil {
@@ -88,6 +88,8 @@ type ClientConfig struct {
 	SystemOSVersion       string  `json:"osVersion"`
 	SystemArch            string  `json:"architecture"`
 	AccountName           *string `json:"account_name"`
+	// Handled by the SDK, not sent to the core
+	FileIntegrity bool `json:"-"`
 }
 
 func NewDefaultConfig() ClientConfig {
@@ -15,7 +15,7 @@ var coreWASM []byte
 // In empirical tests, we determined that maximum message size that can cross the FFI boundary
 // is ~64MB. Past this limit, the Extism FFI will throw an error and the program will crash.
 // We set the limit to 50MB to be safe, to be reconsidered upon further testing.
-const messageLimit = 50 * 1024 * 1024
+const MessageLimit = 50 * 1024 * 1024
 
 const (
 	invokeFuncName        = "invoke"
@@ -67,8 +67,8 @@ func (c *ExtismCore) InitClient(ctx context.Context, config []byte) ([]byte, err
 
 // Invoke calls specified business logic from core
 func (c *ExtismCore) Invoke(ctx context.Context, invokeConfig []byte) ([]byte, error) {
-	if len(invokeConfig) > messageLimit {
-		return nil, fmt.Errorf("message size exceeds the limit of %d bytes, please contact 1Password at support@1password.com or https://developer.1password.com/joinslack if you need help", messageLimit)
+	if len(invokeConfig) > MessageLimit {
+		return nil, fmt.Errorf("message size exceeds the limit of %d bytes, please contact 1Password at support@1password.com or https://developer.1password.com/joinslack if you need help", MessageLimit)
 	}
 	res, err := c.callWithCtx(ctx, invokeFuncName, invokeConfig)
 	if err != nil {
@@ -0,0 +1,413 @@
+package onepassword
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// itemTagName is the struct tag read by MarshalItem and UnmarshalItem.
+//
+// The tag is a comma separated list of key=value options:
+//   - section: the title of the section holding the field. Omit it for built-in fields such as username and password.
+//   - field: the title of the field. Defaults to the name of the struct field.
+//   - id: the ID of the field. Defaults to the field title, prefixed by the section ID for fields within a section.
+//   - type: the ItemFieldType of the field, e.g. Text, Concealed or Date. Defaults to a type inferred from the Go type.
+//
+// A struct-typed field that is not a MonthYear, AddressFieldDetails or time.Time is treated as a group of fields;
+// its `section` option applies to all nested fields which don't set their own. Fields tagged with `op:"-"` are skipped.
+const itemTagName = "op"
+
+const (
+	// dateLayout is the value format of Date fields.
+	dateLayout = "2006-01-02"
+	// monthYearLayout is the value format of MonthYear fields.
+	monthYearLayout = "01/2006"
+)
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	monthYearType = reflect.TypeOf(MonthYear{})
+	addressType   = reflect.TypeOf(AddressFieldDetails{})
+)
+
+// MonthYear is the value of a MonthYear field, such as the expiry date of a credit card.
+type MonthYear struct {
+	Month time.Month
+	Year  int
+}
+
+// String formats the month and year the way 1Password stores them, e.g. "07/2027".
+func (m MonthYear) String() string {
+	return fmt.Sprintf("%02d/%04d", int(m.Month), m.Year)
+}
+
+// ParseMonthYear parses the value of a MonthYear field.
+func ParseMonthYear(value string) (MonthYear, error) {
+	t, err := time.Parse(monthYearLayout, value)
+	if err != nil {
+		return MonthYear{}, fmt.Errorf("invalid month-year value %q, expected MM/YYYY", value)
+	}
+	return MonthYear{Month: t.Month(), Year: t.Year()}, nil
+}
+
+// itemFieldTag is the parsed form of an `op` struct tag.
+type itemFieldTag struct {
+	section   string
+	title     string
+	id        string
+	fieldType ItemFieldType
+}
+
+func parseItemFieldTag(field reflect.StructField, parentSection string) (itemFieldTag, bool, error) {
+	tag := itemFieldTag{section: parentSection, title: field.Name}
+	raw, ok := field.Tag.Lookup(itemTagName)
+	if !ok || raw == "-" {
+		return tag, false, nil
+	}
+	for _, option := range strings.Split(raw, ",") {
+		if option == "" {
+			continue
+		}
+		key, value, found := strings.Cut(option, "=")
+		if !found {
+			return tag, false, fmt.Errorf("invalid %s tag option %q on field %s", itemTagName, option, field.Name)
+		}
+		switch strings.TrimSpace(key) {
+		case "section":
+			tag.section = value
+		case "field":
+			tag.title = value
+		case "id":
+			tag.id = value
+		case "type":
+			tag.fieldType = ItemFieldType(value)
+		default:
+			return tag, false, fmt.Errorf("unknown %s tag option %q on field %s", itemTagName, key, field.Name)
+		}
+	}
+	return tag, true, nil
+}
+
+// isItemFieldGroup reports whether values of type t are expanded into their own fields rather than stored in a single field.
+func isItemFieldGroup(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeType && t != monthYearType && t != addressType
+}
+
+// MarshalItem converts a struct annotated with `op` tags into the fields and sections of ItemCreateParams.
+// The caller is responsible for setting the category, vault ID and title of the returned params.
+func MarshalItem(v any) (ItemCreateParams, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return ItemCreateParams{}, fmt.Errorf("cannot marshal nil %s into an item", rv.Type())
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return ItemCreateParams{}, fmt.Errorf("cannot marshal %s into an item, expected a struct", rv.Type())
+	}
+
+	var params ItemCreateParams
+	sectionIDs := map[string]string{}
+	if err := marshalItemFields(rv, "", &params, sectionIDs); err != nil {
+		return ItemCreateParams{}, err
+	}
+	return params, nil
+}
+
+func marshalItemFields(rv reflect.Value, section string, params *ItemCreateParams, sectionIDs map[string]string) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		structField := rt.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+		tag, tagged, err := parseItemFieldTag(structField, section)
+		if err != nil {
+			return err
+		}
+		value := rv.Field(i)
+		fieldType := structField.Type
+		if fieldType.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				continue
+			}
+			value = value.Elem()
+			fieldType = fieldType.Elem()
+		}
+		if isItemFieldGroup(fieldType) {
+			if tagged || structField.Anonymous {
+				if err := marshalItemFields(value, tag.section, params, sectionIDs); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if !tagged {
+			continue
+		}
+
+		field, err := marshalItemField(value, tag)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", structField.Name, err)
+		}
+		if tag.section != "" {
+			sectionID, ok := sectionIDs[tag.section]
+			if !ok {
+				sectionID = itemSectionID(tag.section)
+				sectionIDs[tag.section] = sectionID
+				params.Sections = append(params.Sections, ItemSection{ID: sectionID, Title: tag.section})
+			}
+			field.SectionID = &sectionID
+			if tag.id == "" {
+				field.ID = sectionID + "." + itemSectionID(tag.title)
+			}
+		}
+		params.Fields = append(params.Fields, field)
+	}
+	return nil
+}
+
+func marshalItemField(value reflect.Value, tag itemFieldTag) (ItemField, error) {
+	field := ItemField{
+		ID:        tag.id,
+		Title:     tag.title,
+		FieldType: tag.fieldType,
+	}
+	if field.ID == "" {
+		field.ID = tag.title
+	}
+
+	switch value.Type() {
+	case timeType:
+		field.Value = value.Interface().(time.Time).Format(dateLayout)
+		if field.FieldType == "" {
+			field.FieldType = ItemFieldTypeDate
+		}
+		return field, nil
+	case monthYearType:
+		field.Value = value.Interface().(MonthYear).String()
+		if field.FieldType == "" {
+			field.FieldType = ItemFieldTypeMonthYear
+		}
+		return field, nil
+	case addressType:
+		address := value.Interface().(AddressFieldDetails)
+		details := NewItemFieldDetailsTypeVariantAddress(&address)
+		field.Details = &details
+		if field.FieldType == "" {
+			field.FieldType = ItemFieldTypeAddress
+		}
+		return field, nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		field.Value = value.String()
+	case reflect.Bool:
+		field.Value = strconv.FormatBool(value.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		field.Value = strconv.FormatInt(value.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		field.Value = strconv.FormatUint(value.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		field.Value = strconv.FormatFloat(value.Float(), 'f', -1, value.Type().Bits())
+	default:
+		return ItemField{}, fmt.Errorf("unsupported type %s", value.Type())
+	}
+	if field.FieldType == "" {
+		field.FieldType = ItemFieldTypeText
+	}
+	return field, nil
+}
+
+// itemSectionID derives a stable section ID from a section title.
+func itemSectionID(title string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		default:
+			return '_'
+		}
+	}, title)
+}
+
+// UnmarshalItem populates the struct pointed to by v with the values of the item's fields, using the `op` struct tags
+// described in MarshalItem. Fields are matched by section (title or ID) and field (title or ID).
+// Struct fields without a matching item field are left untouched.
+func UnmarshalItem(item Item, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("cannot unmarshal an item into %T, expected a non-nil pointer to a struct", v)
+	}
+	rv = rv.Elem()
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot unmarshal an item into %T, expected a non-nil pointer to a struct", v)
+	}
+	return unmarshalItemFields(item, rv, "")
+}
+
+func unmarshalItemFields(item Item, rv reflect.Value, section string) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		structField := rt.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+		tag, tagged, err := parseItemFieldTag(structField, section)
+		if err != nil {
+			return err
+		}
+		value := rv.Field(i)
+		fieldType := structField.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+
+		if isItemFieldGroup(fieldType) {
+			if !tagged && !structField.Anonymous {
+				continue
+			}
+			if value.Kind() == reflect.Pointer {
+				if value.IsNil() {
+					value.Set(reflect.New(fieldType))
+				}
+				value = value.Elem()
+			}
+			if err := unmarshalItemFields(item, value, tag.section); err != nil {
+				return err
+			}
+			continue
+		}
+		if !tagged {
+			continue
+		}
+
+		field, ok := findItemField(item, tag)
+		if !ok {
+			continue
+		}
+		if value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				value.Set(reflect.New(fieldType))
+			}
+			value = value.Elem()
+		}
+		if err := unmarshalItemField(field, value); err != nil {
+			return fmt.Errorf("field %s: %w", structField.Name, err)
+		}
+	}
+	return nil
+}
+
+func findItemField(item Item, tag itemFieldTag) (ItemField, bool) {
+	var sectionIDs []string
+	if tag.section != "" {
+		for _, section := range item.Sections {
+			if section.ID == tag.section || section.Title == tag.section {
+				sectionIDs = append(sectionIDs, section.ID)
+			}
+		}
+		if len(sectionIDs) == 0 {
+			return ItemField{}, false
+		}
+	}
+
+	for _, field := range item.Fields {
+		if tag.id != "" {
+			if field.ID != tag.id {
+				continue
+			}
+		} else if field.Title != tag.title {
+			continue
+		}
+		if tag.section == "" {
+			if field.SectionID == nil || *field.SectionID == "" {
+				return field, true
+			}
+			continue
+		}
+		if field.SectionID == nil {
+			continue
+		}
+		for _, id := range sectionIDs {
+			if *field.SectionID == id {
+				return field, true
+			}
+		}
+	}
+	return ItemField{}, false
+}
+
+func unmarshalItemField(field ItemField, value reflect.Value) error {
+	switch value.Type() {
+	case timeType:
+		if field.Value == "" {
+			value.Set(reflect.Zero(timeType))
+			return nil
+		}
+		t, err := time.Parse(dateLayout, field.Value)
+		if err != nil {
+			return fmt.Errorf("invalid date value %q, expected YYYY-MM-DD", field.Value)
+		}
+		value.Set(reflect.ValueOf(t))
+		return nil
+	case monthYearType:
+		if field.Value == "" {
+			value.Set(reflect.Zero(monthYearType))
+			return nil
+		}
+		monthYear, err := ParseMonthYear(field.Value)
+		if err != nil {
+			return err
+		}
+		value.Set(reflect.ValueOf(monthYear))
+		return nil
+	case addressType:
+		if field.Details == nil || field.Details.Type != ItemFieldDetailsTypeVariantAddress {
+			return fmt.Errorf("item field %q has no address details", field.Title)
+		}
+		if address := field.Details.Address(); address != nil {
+			value.Set(reflect.ValueOf(*address))
+		}
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(field.Value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(field.Value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean value %q", field.Value)
+		}
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(field.Value, 10, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer value %q", field.Value)
+		}
+		value.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(field.Value, 10, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid unsigned integer value %q", field.Value)
+		}
+		value.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(field.Value, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number value %q", field.Value)
+		}
+		value.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", value.Type())
+	}
+	return nil
+}
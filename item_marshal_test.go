@@ -0,0 +1,93 @@
+package onepassword
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testDatabaseCredentials struct {
+	Username string `op:"field=username"`
+	Password string `op:"field=password,type=Concealed"`
+
+	Connection struct {
+		Host string `op:"field=host"`
+		Port int    `op:"field=port"`
+		TLS  *bool  `op:"field=tls"`
+	} `op:"section=Connection"`
+
+	Rotation struct {
+		RotatedAt time.Time `op:"field=rotated at"`
+		ExpiresIn MonthYear `op:"field=expires"`
+	} `op:"section=Rotation"`
+
+	Office   AddressFieldDetails `op:"section=Connection,field=office"`
+	Ignored  string              `op:"-"`
+	Untagged string
+}
+
+func TestMarshalItemRoundTrip(t *testing.T) {
+	tls := true
+	in := testDatabaseCredentials{
+		Username: "admin",
+		Password: "hunter2",
+		Office:   AddressFieldDetails{Street: "4711 Main St", City: "Toronto", Country: "ca"},
+		Ignored:  "ignored",
+		Untagged: "untagged",
+	}
+	in.Connection.Host = "db.internal"
+	in.Connection.Port = 5432
+	in.Connection.TLS = &tls
+	in.Rotation.RotatedAt = time.Date(2026, time.March, 4, 0, 0, 0, 0, time.UTC)
+	in.Rotation.ExpiresIn = MonthYear{Month: time.July, Year: 2027}
+
+	params, err := MarshalItem(&in)
+	require.NoError(t, err)
+
+	assert.Equal(t, []ItemSection{{ID: "connection", Title: "Connection"}, {ID: "rotation", Title: "Rotation"}}, params.Sections)
+	require.Len(t, params.Fields, 8)
+	assert.Equal(t, "password", params.Fields[1].ID)
+	assert.Equal(t, ItemFieldTypeConcealed, params.Fields[1].FieldType)
+	assert.Nil(t, params.Fields[1].SectionID)
+	assert.Equal(t, "connection.host", params.Fields[2].ID)
+	assert.Equal(t, "connection", *params.Fields[2].SectionID)
+	assert.Equal(t, "5432", params.Fields[3].Value)
+	assert.Equal(t, ItemFieldTypeDate, params.Fields[5].FieldType)
+	assert.Equal(t, "2026-03-04", params.Fields[5].Value)
+	assert.Equal(t, ItemFieldTypeMonthYear, params.Fields[6].FieldType)
+	assert.Equal(t, "07/2027", params.Fields[6].Value)
+	assert.Equal(t, ItemFieldTypeAddress, params.Fields[7].FieldType)
+
+	item := Item{Fields: params.Fields, Sections: params.Sections}
+	var out testDatabaseCredentials
+	require.NoError(t, UnmarshalItem(item, &out))
+
+	in.Ignored = ""
+	in.Untagged = ""
+	assert.Equal(t, in, out)
+}
+
+func TestUnmarshalItemMatchesSectionByTitle(t *testing.T) {
+	sectionID := "abc123"
+	item := Item{
+		Sections: []ItemSection{{ID: sectionID, Title: "Connection"}},
+		Fields: []ItemField{
+			{ID: "x1", Title: "host", SectionID: &sectionID, FieldType: ItemFieldTypeText, Value: "db.internal"},
+			{ID: "x2", Title: "port", SectionID: &sectionID, FieldType: ItemFieldTypeText, Value: "not a number"},
+		},
+	}
+
+	var host struct {
+		Host string `op:"section=Connection,field=host"`
+	}
+	require.NoError(t, UnmarshalItem(item, &host))
+	assert.Equal(t, "db.internal", host.Host)
+
+	var port struct {
+		Port int `op:"section=Connection,field=port"`
+	}
+	assert.Error(t, UnmarshalItem(item, &port))
+	assert.Error(t, UnmarshalItem(item, port))
+}
@@ -26,15 +26,45 @@ type ItemsAPI interface {
 	// Update an existing item.
 	Put(ctx context.Context, item Item) (Item, error)
 
+	// Apply a partial update to the latest version of an item, retrying when the item was changed concurrently.
+	Patch(ctx context.Context, vaultID string, itemID string, patch ItemPatch) (Item, error)
+
+	// Update an existing item by applying a mutation to its latest version, retrying when the item was changed concurrently.
+	Update(ctx context.Context, vaultID string, itemID string, mutate func(*Item) error, opts ...ItemUpdateOptions) (Item, error)
+
 	// Delete an item.
 	Delete(ctx context.Context, vaultID string, itemID string) error
 
 	// Delete items in batch, within a single vault.
 	DeleteAll(ctx context.Context, vaultID string, itemIds []string) (ItemsDeleteAllResponse, error)
 
+	// Create items in batch, across multiple vaults.
+	CreateAllAcrossVaults(ctx context.Context, params []ItemCreateParams) (ItemsUpdateAllResponse, error)
+
+	// Get items in batch, across multiple vaults.
+	GetAllAcrossVaults(ctx context.Context, itemIDs []VaultItemID) (ItemsGetAllAcrossVaultsResponse, error)
+
+	// Delete items in batch, across multiple vaults.
+	DeleteAllAcrossVaults(ctx context.Context, itemIDs []VaultItemID) (ItemsDeleteAllAcrossVaultsResponse, error)
+
 	// Archive an item.
 	Archive(ctx context.Context, vaultID string, itemID string) error
 
+	// Restore an archived item.
+	Restore(ctx context.Context, vaultID string, itemID string) error
+
+	// List the previous versions of an item, newest first.
+	History(ctx context.Context, vaultID string, itemID string) ([]ItemHistoryEntry, error)
+
+	// Roll an item back to a previous version.
+	Rollback(ctx context.Context, vaultID string, itemID string, version uint32) (Item, error)
+
+	// Copy an item into another vault, including its files and document.
+	Copy(ctx context.Context, vaultID string, itemID string, destinationVaultID string) (Item, error)
+
+	// Move an item into another vault, including its files and document.
+	Move(ctx context.Context, vaultID string, itemID string, destinationVaultID string) (Item, error)
+
 	// List items based on filters.
 	List(ctx context.Context, vaultID string, filters ...ItemListFilter) ([]ItemOverview, error)
 
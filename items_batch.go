@@ -0,0 +1,267 @@
+package onepassword
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/1password/onepassword-sdk-go/internal"
+)
+
+const (
+	// batchInvocationOverhead bounds the size of the rest of a batch invocation: the client ID, the method name and the
+	// vault ID.
+	batchInvocationOverhead = 64 * 1024
+	// maxConcurrentBatches is the maximum number of batch invocations in flight at the same time.
+	maxConcurrentBatches = 4
+)
+
+// maxBatchSize is the maximum serialized size of the inputs sent in a single batch invocation, so that the invocation
+// stays below the message limit of the core. Tests lower it to split small batches.
+var maxBatchSize = internal.MessageLimit - batchInvocationOverhead
+
+// batchedItemsSource is the ItemsAPI of clients. It splits the batches of CreateAll, GetAll and DeleteAll that don't
+// fit in a single invocation into multiple invocations.
+type batchedItemsSource struct {
+	*ItemsSource
+}
+
+// Create items in batch, within a single vault.
+// Large batches are split into multiple invocations. If an invocation fails, the remaining batches aren't sent and the
+// error is returned together with the responses: items of completed batches have their result, the others have an
+// internal error saying whether their batch failed or wasn't sent.
+func (i batchedItemsSource) CreateAll(ctx context.Context, vaultID string, params []ItemCreateParams) (ItemsUpdateAllResponse, error) {
+	return i.createAllChunked(ctx, vaultID, params)
+}
+
+// Get items by vault and their item IDs.
+// Large batches are split into multiple invocations. If an invocation fails, the remaining batches aren't sent and
+// the error is returned together with the responses of the completed batches; the other items have an internal error.
+func (i batchedItemsSource) GetAll(ctx context.Context, vaultID string, itemIds []string) (ItemsGetAllResponse, error) {
+	return i.getAllChunked(ctx, vaultID, itemIds)
+}
+
+// Delete items in batch, within a single vault.
+// Large batches are split into multiple invocations. If an invocation fails, the remaining batches aren't sent and the
+// error is returned together with the responses: items of completed batches have their result, the others have an
+// internal error saying whether their batch failed or wasn't sent.
+func (i batchedItemsSource) DeleteAll(ctx context.Context, vaultID string, itemIds []string) (ItemsDeleteAllResponse, error) {
+	return i.deleteAllChunked(ctx, vaultID, itemIds)
+}
+
+// batchRange is a contiguous range [start, end) of the inputs of a batch operation that is sent in a single invocation.
+type batchRange struct {
+	start int
+	end   int
+	// oversized is set for a range holding a single input that is too big to be sent on its own.
+	oversized bool
+}
+
+// splitBatch splits inputs with the given serialized sizes into ranges whose serialized JSON array fits in maxSize.
+func splitBatch(sizes []int, maxSize int) []batchRange {
+	var ranges []batchRange
+	start, size := 0, 0
+	for i, s := range sizes {
+		// Every input is followed by a comma or the closing bracket of the array.
+		s++
+		if s > maxSize {
+			if start < i {
+				ranges = append(ranges, batchRange{start: start, end: i})
+			}
+			ranges = append(ranges, batchRange{start: i, end: i + 1, oversized: true})
+			start, size = i+1, 0
+			continue
+		}
+		if i > start && size+s > maxSize {
+			ranges = append(ranges, batchRange{start: start, end: i})
+			start, size = i, 0
+		}
+		size += s
+	}
+	if start < len(sizes) {
+		ranges = append(ranges, batchRange{start: start, end: len(sizes)})
+	}
+	return ranges
+}
+
+// serializedSizes returns the JSON encoded size of each input.
+func serializedSizes[T any](inputs []T) ([]int, error) {
+	sizes := make([]int, len(inputs))
+	for i, input := range inputs {
+		encoded, err := json.Marshal(input)
+		if err != nil {
+			return nil, err
+		}
+		sizes[i] = len(encoded)
+	}
+	return sizes, nil
+}
+
+// runBatches calls fn for every range, running at most maxConcurrentBatches at the same time.
+// The first error cancels the batches that weren't sent yet and is returned. fn is not called for those batches,
+// so the caller must treat the inputs of ranges without results as not sent.
+func runBatches(ctx context.Context, ranges []batchRange, fn func(ctx context.Context, r batchRange) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, maxConcurrentBatches)
+	for _, r := range ranges {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(r batchRange) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := fn(ctx, r); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(r)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// batchFailedMessage describes the outcome of the inputs of a batch whose invocation failed, which may or may not
+// have been applied.
+func batchFailedMessage(err error) ErrorMessage {
+	return ErrorMessage(fmt.Sprintf("the batch holding this item failed, the outcome is unknown: %v", err))
+}
+
+// batchNotSentMessage describes the outcome of the inputs of a batch that was never sent because an earlier batch failed.
+func batchNotSentMessage(err error) ErrorMessage {
+	return ErrorMessage(fmt.Sprintf("the batch holding this item was not sent: %v", err))
+}
+
+// createAllChunked creates items in batch, within a single vault, splitting large batches into multiple invocations.
+// The individual responses are in the same order as params.
+// If an invocation fails, the remaining batches aren't sent and the error is returned together with the responses:
+// items of completed batches have their result, the others have an internal error saying whether their batch failed
+// or wasn't sent.
+func (i ItemsSource) createAllChunked(ctx context.Context, vaultID string, params []ItemCreateParams) (ItemsUpdateAllResponse, error) {
+	sizes, err := serializedSizes(params)
+	if err != nil {
+		return ItemsUpdateAllResponse{}, err
+	}
+
+	responses := make([]Response[Item, ItemUpdateFailureReason], len(params))
+	err = runBatches(ctx, splitBatch(sizes, maxBatchSize), func(ctx context.Context, r batchRange) error {
+		if r.oversized {
+			reason := NewItemUpdateFailureReasonTypeVariantItemStatusTooBig()
+			responses[r.start] = Response[Item, ItemUpdateFailureReason]{Error: &reason}
+			return nil
+		}
+		result, err := i.CreateAll(ctx, vaultID, params[r.start:r.end])
+		if err == nil && len(result.IndividualResponses) != r.end-r.start {
+			err = fmt.Errorf("expected %d responses when creating items in batch, got %d", r.end-r.start, len(result.IndividualResponses))
+		}
+		if err != nil {
+			reason := NewItemUpdateFailureReasonTypeVariantInternal(batchFailedMessage(err))
+			for j := r.start; j < r.end; j++ {
+				responses[j] = Response[Item, ItemUpdateFailureReason]{Error: &reason}
+			}
+			return err
+		}
+		copy(responses[r.start:r.end], result.IndividualResponses)
+		return nil
+	})
+	if err != nil {
+		reason := NewItemUpdateFailureReasonTypeVariantInternal(batchNotSentMessage(err))
+		for j := range responses {
+			if responses[j].Content == nil && responses[j].Error == nil {
+				responses[j] = Response[Item, ItemUpdateFailureReason]{Error: &reason}
+			}
+		}
+	}
+	return ItemsUpdateAllResponse{IndividualResponses: responses}, err
+}
+
+// getAllChunked gets items by vault and their item IDs, splitting large batches into multiple invocations.
+// The individual responses are in the same order as itemIds.
+// If an invocation fails, the remaining batches aren't sent and the error is returned together with the responses
+// of the completed batches; the other items have an internal error.
+func (i ItemsSource) getAllChunked(ctx context.Context, vaultID string, itemIds []string) (ItemsGetAllResponse, error) {
+	sizes, err := serializedSizes(itemIds)
+	if err != nil {
+		return ItemsGetAllResponse{}, err
+	}
+
+	responses := make([]Response[Item, ItemsGetAllError], len(itemIds))
+	err = runBatches(ctx, splitBatch(sizes, maxBatchSize), func(ctx context.Context, r batchRange) error {
+		result, err := i.GetAll(ctx, vaultID, itemIds[r.start:r.end])
+		if err == nil && len(result.IndividualResponses) != r.end-r.start {
+			err = fmt.Errorf("expected %d responses when getting items in batch, got %d", r.end-r.start, len(result.IndividualResponses))
+		}
+		if err != nil {
+			return err
+		}
+		copy(responses[r.start:r.end], result.IndividualResponses)
+		return nil
+	})
+	if err != nil {
+		getErr := NewItemsGetAllErrorTypeVariantInternal(ErrorMessage(err.Error()))
+		for j := range responses {
+			if responses[j].Content == nil && responses[j].Error == nil {
+				responses[j] = Response[Item, ItemsGetAllError]{Error: &getErr}
+			}
+		}
+	}
+	return ItemsGetAllResponse{IndividualResponses: responses}, err
+}
+
+// deleteAllChunked deletes items in batch, within a single vault, splitting large batches into multiple invocations.
+// The individual responses are keyed by item ID.
+// If an invocation fails, the remaining batches aren't sent and the error is returned together with the responses:
+// items of completed batches have their result, the others have an internal error saying whether their batch failed
+// or wasn't sent.
+func (i ItemsSource) deleteAllChunked(ctx context.Context, vaultID string, itemIds []string) (ItemsDeleteAllResponse, error) {
+	sizes, err := serializedSizes(itemIds)
+	if err != nil {
+		return ItemsDeleteAllResponse{}, err
+	}
+
+	var mu sync.Mutex
+	responses := make(map[string]Response[struct{}, ItemUpdateFailureReason], len(itemIds))
+	err = runBatches(ctx, splitBatch(sizes, maxBatchSize), func(ctx context.Context, r batchRange) error {
+		result, err := i.DeleteAll(ctx, vaultID, itemIds[r.start:r.end])
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			reason := NewItemUpdateFailureReasonTypeVariantInternal(batchFailedMessage(err))
+			for _, id := range itemIds[r.start:r.end] {
+				responses[id] = Response[struct{}, ItemUpdateFailureReason]{Error: &reason}
+			}
+			return err
+		}
+		for id, response := range result.IndividualResponses {
+			responses[id] = response
+		}
+		return nil
+	})
+	if err != nil {
+		reason := NewItemUpdateFailureReasonTypeVariantInternal(batchNotSentMessage(err))
+		for _, id := range itemIds {
+			if _, ok := responses[id]; !ok {
+				responses[id] = Response[struct{}, ItemUpdateFailureReason]{Error: &reason}
+			}
+		}
+	}
+	return ItemsDeleteAllResponse{IndividualResponses: responses}, err
+}
@@ -0,0 +1,182 @@
+package onepassword
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/1password/onepassword-sdk-go/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitBatch(t *testing.T) {
+	assert.Empty(t, splitBatch(nil, 10))
+
+	// Every input takes its size and a separator
+	assert.Equal(t, []batchRange{{start: 0, end: 2}, {start: 2, end: 4}, {start: 4, end: 5}}, splitBatch([]int{1, 1, 1, 1, 1}, 4))
+	assert.Equal(t, []batchRange{{start: 0, end: 2}, {start: 2, end: 3}}, splitBatch([]int{3, 5, 5}, 10))
+
+	// Inputs bigger than the size limit are isolated
+	assert.Equal(t, []batchRange{{start: 0, end: 1}, {start: 1, end: 2, oversized: true}, {start: 2, end: 3}}, splitBatch([]int{3, 10, 3}, 10))
+}
+
+// withMaxBatchSize lowers the size of batches for the duration of a test, so that it fits count inputs the size of
+// input.
+func withMaxBatchSize(t *testing.T, count int, input any) {
+	encoded, err := json.Marshal(input)
+	require.NoError(t, err)
+	previous := maxBatchSize
+	maxBatchSize = count * (len(encoded) + 1)
+	t.Cleanup(func() { maxBatchSize = previous })
+}
+
+// fakeBatchCore creates items in batch, failing the batches that start with the item titled failTitle.
+type fakeBatchCore struct {
+	failTitle   string
+	invocations atomic.Int32
+}
+
+func (c *fakeBatchCore) InitClient(ctx context.Context, config []byte) ([]byte, error) {
+	return []byte("1"), nil
+}
+
+func (c *fakeBatchCore) Invoke(ctx context.Context, invokeConfig []byte) ([]byte, error) {
+	name, params, err := parseInvocation(invokeConfig)
+	if err != nil {
+		return nil, err
+	}
+	if name != "ItemsCreateAll" {
+		return nil, errors.New("unexpected invocation " + name)
+	}
+	c.invocations.Add(1)
+	var items []ItemCreateParams
+	if err := json.Unmarshal(params["params"], &items); err != nil {
+		return nil, err
+	}
+	if items[0].Title == c.failTitle {
+		return nil, errors.New(`{"name":"","message":"connection reset"}`)
+	}
+	var response ItemsUpdateAllResponse
+	for _, item := range items {
+		created := Item{ID: "id-" + item.Title, Title: item.Title}
+		response.IndividualResponses = append(response.IndividualResponses, Response[Item, ItemUpdateFailureReason]{Content: &created})
+	}
+	return json.Marshal(response)
+}
+
+func (c *fakeBatchCore) ReleaseClient(clientID []byte) {}
+
+func TestClientSplitsBatches(t *testing.T) {
+	core := &fakeBatchCore{}
+	client, err := initClient(context.Background(), internal.CoreWrapper{InnerCore: core}, Client{})
+	require.NoError(t, err)
+	params := make([]ItemCreateParams, 250)
+	for i := range params {
+		params[i] = ItemCreateParams{Title: fmt.Sprintf("item%03d", i)}
+	}
+	withMaxBatchSize(t, 100, params[0])
+
+	response, err := client.Items().CreateAll(context.Background(), "vault1", params)
+	require.NoError(t, err)
+	require.Len(t, response.IndividualResponses, len(params))
+	for i, res := range response.IndividualResponses {
+		require.NotNil(t, res.Content, i)
+		assert.Equal(t, params[i].Title, res.Content.Title)
+	}
+	assert.Equal(t, int32(3), core.invocations.Load())
+}
+
+func TestCreateAllKeepsPartialResults(t *testing.T) {
+	items := batchedItemsSource{ItemsSource: &ItemsSource{InnerClient: &internal.InnerClient{Core: internal.CoreWrapper{InnerCore: &fakeBatchCore{failTitle: "item100"}}}}}
+	params := make([]ItemCreateParams, 150)
+	for i := range params {
+		params[i] = ItemCreateParams{Title: fmt.Sprintf("item%03d", i)}
+	}
+	withMaxBatchSize(t, 100, params[0])
+
+	response, err := items.CreateAll(context.Background(), "vault1", params)
+	require.EqualError(t, err, "connection reset")
+	require.Len(t, response.IndividualResponses, len(params))
+	for i, res := range response.IndividualResponses {
+		if i < 100 {
+			require.NotNil(t, res.Content, i)
+			assert.Equal(t, params[i].Title, res.Content.Title)
+			continue
+		}
+		require.NotNil(t, res.Error, i)
+		assert.Equal(t, ItemUpdateFailureReasonTypeVariantInternal, res.Error.Type)
+		assert.Contains(t, string(res.Error.Internal()), "the outcome is unknown")
+	}
+}
+
+func TestCreateAllAcrossVaultsKeepsPartialResults(t *testing.T) {
+	items := NewItemsSource(&internal.InnerClient{Core: internal.CoreWrapper{InnerCore: &fakeBatchCore{failTitle: "item100"}}})
+	var params []ItemCreateParams
+	for i := 0; i < 110; i++ {
+		params = append(params, ItemCreateParams{VaultID: "vault1", Title: fmt.Sprintf("item%03d", i)})
+	}
+	params = append(params, ItemCreateParams{VaultID: "vault2", Title: "other"})
+	withMaxBatchSize(t, 100, params[0])
+
+	response, err := items.CreateAllAcrossVaults(context.Background(), params)
+	require.NoError(t, err)
+	require.Len(t, response.IndividualResponses, len(params))
+	assert.NotNil(t, response.IndividualResponses[0].Content, "items of completed batches keep their result")
+	assert.NotNil(t, response.IndividualResponses[100].Error)
+	assert.Equal(t, "other", response.IndividualResponses[len(params)-1].Content.Title)
+}
+
+// fakeGetAllCore gets items in batch, calling afterGetAll after every batch.
+type fakeGetAllCore struct {
+	afterGetAll func()
+}
+
+func (c *fakeGetAllCore) InitClient(ctx context.Context, config []byte) ([]byte, error) {
+	return []byte("1"), nil
+}
+
+func (c *fakeGetAllCore) Invoke(ctx context.Context, invokeConfig []byte) ([]byte, error) {
+	name, params, err := parseInvocation(invokeConfig)
+	if err != nil {
+		return nil, err
+	}
+	if name != "ItemsGetAll" {
+		return nil, errors.New("unexpected invocation " + name)
+	}
+	var vaultID string
+	var itemIDs []string
+	if err := errors.Join(json.Unmarshal(params["vault_id"], &vaultID), json.Unmarshal(params["item_ids"], &itemIDs)); err != nil {
+		return nil, err
+	}
+	var response ItemsGetAllResponse
+	for _, id := range itemIDs {
+		item := Item{ID: id, VaultID: vaultID}
+		response.IndividualResponses = append(response.IndividualResponses, Response[Item, ItemsGetAllError]{Content: &item})
+	}
+	c.afterGetAll()
+	return json.Marshal(response)
+}
+
+func (c *fakeGetAllCore) ReleaseClient(clientID []byte) {}
+
+func TestGetAllAcrossVaultsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	items := NewItemsSource(&internal.InnerClient{Core: internal.CoreWrapper{InnerCore: &fakeGetAllCore{afterGetAll: cancel}}})
+	itemIDs := []VaultItemID{{VaultID: "vault1", ItemID: "item1"}, {VaultID: "vault2", ItemID: "item2"}, {VaultID: "vault1", ItemID: "item3"}}
+
+	response, err := items.GetAllAcrossVaults(ctx, itemIDs)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Len(t, response.IndividualResponses, len(itemIDs))
+	for _, id := range []VaultItemID{itemIDs[0], itemIDs[2]} {
+		require.NotNil(t, response.IndividualResponses[id].Content, "items of completed vaults keep their result")
+		assert.Equal(t, id.ItemID, response.IndividualResponses[id].Content.ID)
+	}
+	notSent := response.IndividualResponses[itemIDs[1]]
+	require.NotNil(t, notSent.Error)
+	assert.Contains(t, string(notSent.Error.Internal()), "was not sent")
+}
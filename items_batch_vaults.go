@@ -0,0 +1,152 @@
+package onepassword
+
+import (
+	"context"
+)
+
+// Identifies an item by the ID of its vault and its own ID.
+type VaultItemID struct {
+	// The ID of the vault where the item is saved
+	VaultID string
+	// The item's ID
+	ItemID string
+}
+
+// Response of a cross-vault batch get, keyed by vault and item ID.
+type ItemsGetAllAcrossVaultsResponse struct {
+	IndividualResponses map[VaultItemID]Response[Item, ItemsGetAllError]
+}
+
+// Response of a cross-vault batch delete, keyed by vault and item ID.
+type ItemsDeleteAllAcrossVaultsResponse struct {
+	IndividualResponses map[VaultItemID]Response[struct{}, ItemUpdateFailureReason]
+}
+
+// groupByVault returns the indices of the inputs grouped by vault ID, with the vaults in order of first appearance.
+func groupByVault[T any](inputs []T, vaultID func(T) string) ([]string, map[string][]int) {
+	var vaults []string
+	indices := map[string][]int{}
+	for i, input := range inputs {
+		id := vaultID(input)
+		if _, ok := indices[id]; !ok {
+			vaults = append(vaults, id)
+		}
+		indices[id] = append(indices[id], i)
+	}
+	return vaults, indices
+}
+
+// Create items in batch, across multiple vaults. The vault of each item is taken from its VaultID.
+// If a vault can't be written to at all, each of its items gets an error response; items created before a batch of
+// the vault failed keep their result. The individual responses are in the same order as params.
+// If ctx is cancelled, the responses gathered so far are returned with the error, and the items of the vaults that
+// weren't processed get an error response.
+func (i ItemsSource) CreateAllAcrossVaults(ctx context.Context, params []ItemCreateParams) (ItemsUpdateAllResponse, error) {
+	responses := make([]Response[Item, ItemUpdateFailureReason], len(params))
+	vaults, indices := groupByVault(params, func(p ItemCreateParams) string { return p.VaultID })
+	for _, vaultID := range vaults {
+		vaultParams := make([]ItemCreateParams, len(indices[vaultID]))
+		for j, index := range indices[vaultID] {
+			vaultParams[j] = params[index]
+		}
+
+		result, err := i.createAllChunked(ctx, vaultID, vaultParams)
+		if len(result.IndividualResponses) == len(vaultParams) {
+			for j, index := range indices[vaultID] {
+				responses[index] = result.IndividualResponses[j]
+			}
+		} else if err != nil {
+			reason := NewItemUpdateFailureReasonTypeVariantInternal(ErrorMessage(err.Error()))
+			for _, index := range indices[vaultID] {
+				responses[index] = Response[Item, ItemUpdateFailureReason]{Error: &reason}
+			}
+		}
+		if ctx.Err() != nil {
+			reason := NewItemUpdateFailureReasonTypeVariantInternal(batchNotSentMessage(ctx.Err()))
+			for j := range responses {
+				if responses[j].Content == nil && responses[j].Error == nil {
+					responses[j] = Response[Item, ItemUpdateFailureReason]{Error: &reason}
+				}
+			}
+			return ItemsUpdateAllResponse{IndividualResponses: responses}, ctx.Err()
+		}
+	}
+	return ItemsUpdateAllResponse{IndividualResponses: responses}, nil
+}
+
+// Get items in batch, across multiple vaults.
+// If a vault can't be read at all, each of its items gets an error response.
+// If ctx is cancelled, the responses gathered so far are returned with the error, and the items of the vaults that
+// weren't processed get an error response.
+func (i ItemsSource) GetAllAcrossVaults(ctx context.Context, itemIDs []VaultItemID) (ItemsGetAllAcrossVaultsResponse, error) {
+	responses := make(map[VaultItemID]Response[Item, ItemsGetAllError], len(itemIDs))
+	vaults, indices := groupByVault(itemIDs, func(id VaultItemID) string { return id.VaultID })
+	for _, vaultID := range vaults {
+		ids := make([]string, len(indices[vaultID]))
+		for j, index := range indices[vaultID] {
+			ids[j] = itemIDs[index].ItemID
+		}
+
+		result, err := i.getAllChunked(ctx, vaultID, ids)
+		if len(result.IndividualResponses) == len(ids) {
+			for j, id := range ids {
+				responses[VaultItemID{VaultID: vaultID, ItemID: id}] = result.IndividualResponses[j]
+			}
+		} else if err != nil {
+			getErr := NewItemsGetAllErrorTypeVariantInternal(ErrorMessage(err.Error()))
+			for _, id := range ids {
+				responses[VaultItemID{VaultID: vaultID, ItemID: id}] = Response[Item, ItemsGetAllError]{Error: &getErr}
+			}
+		}
+		if ctx.Err() != nil {
+			getErr := NewItemsGetAllErrorTypeVariantInternal(batchNotSentMessage(ctx.Err()))
+			for _, id := range itemIDs {
+				if _, ok := responses[id]; !ok {
+					responses[id] = Response[Item, ItemsGetAllError]{Error: &getErr}
+				}
+			}
+			return ItemsGetAllAcrossVaultsResponse{IndividualResponses: responses}, ctx.Err()
+		}
+	}
+	return ItemsGetAllAcrossVaultsResponse{IndividualResponses: responses}, nil
+}
+
+// Delete items in batch, across multiple vaults.
+// If a vault can't be written to at all, each of its items gets an error response; items deleted before a batch of
+// the vault failed keep their result.
+// If ctx is cancelled, the responses gathered so far are returned with the error, and the items of the vaults that
+// weren't processed get an error response.
+func (i ItemsSource) DeleteAllAcrossVaults(ctx context.Context, itemIDs []VaultItemID) (ItemsDeleteAllAcrossVaultsResponse, error) {
+	responses := make(map[VaultItemID]Response[struct{}, ItemUpdateFailureReason], len(itemIDs))
+	vaults, indices := groupByVault(itemIDs, func(id VaultItemID) string { return id.VaultID })
+	for _, vaultID := range vaults {
+		ids := make([]string, len(indices[vaultID]))
+		for j, index := range indices[vaultID] {
+			ids[j] = itemIDs[index].ItemID
+		}
+
+		result, err := i.deleteAllChunked(ctx, vaultID, ids)
+		for id, response := range result.IndividualResponses {
+			responses[VaultItemID{VaultID: vaultID, ItemID: id}] = response
+		}
+		if err != nil {
+			reason := NewItemUpdateFailureReasonTypeVariantInternal(ErrorMessage(err.Error()))
+			for _, id := range ids {
+				key := VaultItemID{VaultID: vaultID, ItemID: id}
+				if _, ok := responses[key]; !ok {
+					responses[key] = Response[struct{}, ItemUpdateFailureReason]{Error: &reason}
+				}
+			}
+		}
+		if ctx.Err() != nil {
+			reason := NewItemUpdateFailureReasonTypeVariantInternal(batchNotSentMessage(ctx.Err()))
+			for _, id := range itemIDs {
+				if _, ok := responses[id]; !ok {
+					responses[id] = Response[struct{}, ItemUpdateFailureReason]{Error: &reason}
+				}
+			}
+			return ItemsDeleteAllAcrossVaultsResponse{IndividualResponses: responses}, ctx.Err()
+		}
+	}
+	return ItemsDeleteAllAcrossVaultsResponse{IndividualResponses: responses}, nil
+}
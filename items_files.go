@@ -5,6 +5,7 @@ package onepassword
 import (
 	"context"
 	"encoding/json"
+	"io"
 
 	"github.com/1password/onepassword-sdk-go/internal"
 )
@@ -21,6 +22,15 @@ type ItemsFilesAPI interface {
 
 	// Replace the document file within a document item.
 	ReplaceDocument(ctx context.Context, item Item, docParams DocumentCreateParams) (Item, error)
+
+	// Open a file of the Item for reading. Its content is downloaded in chunks as it is read.
+	OpenFile(ctx context.Context, vaultID string, itemID string, attr FileAttributes) (io.ReadCloser, error)
+
+	// Attach a file to the Item, uploading its content from a reader in chunks.
+	AttachStream(ctx context.Context, item Item, fileParams FileUploadParams, content io.Reader) (Item, error)
+
+	// Replace the document file within a document item, uploading its content from a reader in chunks.
+	ReplaceDocumentStream(ctx context.Context, item Item, name string, content io.Reader) (Item, error)
 }
 
 type ItemsFilesSource struct {
@@ -0,0 +1,322 @@
+package onepassword
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"slices"
+)
+
+// The section holding the SHA-256 content hashes of an item's files, one concealed field per file. The fields are keyed
+// by the section and field IDs of the file, or documentHashFieldID for the document, so they stay valid when the
+// item is copied and its files get new IDs.
+const (
+	fileIntegritySectionID    = "file_integrity"
+	fileIntegritySectionTitle = "File integrity"
+	documentHashFieldID       = "sha256.document"
+)
+
+// FileIntegrityError is returned when the content of a file doesn't match the SHA-256 hash recorded when it was attached.
+type FileIntegrityError struct {
+	// The attributes of the file
+	File FileAttributes
+	// The hex encoded hash recorded when the file was attached
+	Expected string
+	// The hex encoded hash of the content that was read
+	Actual string
+}
+
+func (e *FileIntegrityError) Error() string {
+	return fmt.Sprintf("content of file %q (%s) doesn't match its recorded SHA-256 hash: expected %s, got %s", e.File.Name, e.File.ID, e.Expected, e.Actual)
+}
+
+// FileHash returns the hex encoded SHA-256 hash of a file's content, as recorded in the item when the file was
+// saved by a client created with WithFileIntegrity, attached with AttachFileVerified, AttachStreamVerified,
+// ReplaceDocumentVerified or ReplaceDocumentStreamVerified, or created with parameters passed through WithFileHashes.
+// The hash is stored as a concealed field, it protects against corruption, not against changes by users with edit access.
+func FileHash(item Item, attr FileAttributes) (string, bool) {
+	id, ok := fileHashFieldID(item, attr)
+	if !ok {
+		return "", false
+	}
+	i := slices.IndexFunc(item.Fields, func(f ItemField) bool { return IsFileHashField(f) && f.ID == id })
+	if i < 0 {
+		return "", false
+	}
+	return item.Fields[i].Value, true
+}
+
+// WithFileHashes returns the parameters with the SHA-256 hashes of their files and document recorded, so that the
+// item is created with them and its files are verified when they're read.
+func WithFileHashes(params ItemCreateParams) ItemCreateParams {
+	for _, file := range params.Files {
+		setFileHash(&params.Sections, &params.Fields, fieldFileHashID(file.SectionID, file.FieldID), file.Name, contentHash(file.Content))
+	}
+	if params.Document != nil {
+		setFileHash(&params.Sections, &params.Fields, documentHashFieldID, params.Document.Name, contentHash(params.Document.Content))
+	}
+	return params
+}
+
+// VerifyFileContent checks the content of one of the item's files, or of its document, against the SHA-256 hash
+// recorded in the item, returning a *FileIntegrityError if they don't match. Files without a recorded hash pass.
+func VerifyFileContent(item Item, attr FileAttributes, content []byte) error {
+	if expected, ok := FileHash(item, attr); ok {
+		if actual := contentHash(content); actual != expected {
+			return &FileIntegrityError{File: attr, Expected: expected, Actual: actual}
+		}
+	}
+	return nil
+}
+
+// ReadFileVerified reads a file's content from the Item, verifying it against the SHA-256 hash recorded in the item.
+// Files without a recorded hash are returned unverified.
+func ReadFileVerified(ctx context.Context, files ItemsFilesAPI, item Item, attr FileAttributes) ([]byte, error) {
+	content, err := unwrapIntegrity(files).Read(ctx, item.VaultID, item.ID, attr)
+	if err != nil {
+		return nil, err
+	}
+	if err := VerifyFileContent(item, attr, content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// OpenFileVerified opens a file of the Item for reading. Its content is verified against the SHA-256 hash recorded
+// in the item once it was read: the read returning the end of the content returns a *FileIntegrityError instead of
+// io.EOF if they don't match, so the content must not be trusted before that.
+func OpenFileVerified(ctx context.Context, files ItemsFilesAPI, item Item, attr FileAttributes) (io.ReadCloser, error) {
+	r, err := unwrapIntegrity(files).OpenFile(ctx, item.VaultID, item.ID, attr)
+	if err != nil {
+		return nil, err
+	}
+	expected, ok := FileHash(item, attr)
+	if !ok {
+		return r, nil
+	}
+	return &verifyingReader{ReadCloser: r, attr: attr, hash: sha256.New(), expected: expected}, nil
+}
+
+// verifyingReader hashes the content read from a file and checks it against the expected hash at its end.
+type verifyingReader struct {
+	io.ReadCloser
+	attr     FileAttributes
+	hash     hash.Hash
+	expected string
+}
+
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.hash.Write(p[:n])
+	if errors.Is(err, io.EOF) {
+		if actual := hex.EncodeToString(r.hash.Sum(nil)); actual != r.expected {
+			return n, &FileIntegrityError{File: r.attr, Expected: r.expected, Actual: actual}
+		}
+	}
+	return n, err
+}
+
+// AttachFileVerified attaches a file to the Item and records the SHA-256 hash of its content in the same update.
+func AttachFileVerified(ctx context.Context, files ItemsFilesAPI, item Item, fileParams FileCreateParams) (Item, error) {
+	setFileHash(&item.Sections, &item.Fields, fieldFileHashID(fileParams.SectionID, fileParams.FieldID), fileParams.Name, contentHash(fileParams.Content))
+	return unwrapIntegrity(files).Attach(ctx, item, fileParams)
+}
+
+// DeleteFileVerified deletes a field file from the Item using the section and field IDs, and removes its recorded
+// hash in the same update.
+func DeleteFileVerified(ctx context.Context, files ItemsFilesAPI, item Item, sectionID string, fieldID string) (Item, error) {
+	removeFileHash(&item.Sections, &item.Fields, fieldFileHashID(sectionID, fieldID))
+	return unwrapIntegrity(files).Delete(ctx, item, sectionID, fieldID)
+}
+
+// ReplaceDocumentVerified replaces the document file within a document item and records the SHA-256 hash of its
+// content in the same update.
+func ReplaceDocumentVerified(ctx context.Context, files ItemsFilesAPI, item Item, docParams DocumentCreateParams) (Item, error) {
+	setFileHash(&item.Sections, &item.Fields, documentHashFieldID, docParams.Name, contentHash(docParams.Content))
+	return unwrapIntegrity(files).ReplaceDocument(ctx, item, docParams)
+}
+
+// AttachStreamVerified attaches a file to the Item, uploading its content from a reader in chunks, and records the
+// SHA-256 hash of its content in the same update. Unless files is the client's Files API, the content must
+// implement io.Seeker, so that it can be hashed before it is attached.
+func AttachStreamVerified(ctx context.Context, files ItemsFilesAPI, item Item, fileParams FileUploadParams, content io.Reader) (Item, error) {
+	id := fieldFileHashID(fileParams.SectionID, fileParams.FieldID)
+	return streamVerified(ctx, unwrapIntegrity(files), item, id, fileParams.Name, content,
+		func(files fileUploader, item Item, uploadID string) (Item, error) {
+			return files.attachUpload(ctx, item, uploadID, fileParams)
+		},
+		func(files ItemsFilesAPI, item Item, content io.Reader) (Item, error) {
+			return files.AttachStream(ctx, item, fileParams, content)
+		})
+}
+
+// ReplaceDocumentStreamVerified replaces the document file within a document item, uploading its content from a
+// reader in chunks, and records the SHA-256 hash of its content in the same update. Unless files is the client's
+// Files API, the content must implement io.Seeker, so that it can be hashed before it is attached.
+func ReplaceDocumentStreamVerified(ctx context.Context, files ItemsFilesAPI, item Item, name string, content io.Reader) (Item, error) {
+	return streamVerified(ctx, unwrapIntegrity(files), item, documentHashFieldID, name, content,
+		func(files fileUploader, item Item, uploadID string) (Item, error) {
+			return files.replaceDocumentUpload(ctx, item, uploadID, name)
+		},
+		func(files ItemsFilesAPI, item Item, content io.Reader) (Item, error) {
+			return files.ReplaceDocumentStream(ctx, item, name, content)
+		})
+}
+
+// fileUploader is implemented by ItemsFilesSource, which can upload content before deciding what to save it with.
+type fileUploader interface {
+	upload(ctx context.Context, content io.Reader) (string, error)
+	abortUpload(ctx context.Context, uploadID string) error
+	attachUpload(ctx context.Context, item Item, uploadID string, fileParams FileUploadParams) (Item, error)
+	replaceDocumentUpload(ctx context.Context, item Item, uploadID string, name string) (Item, error)
+}
+
+// streamVerified saves streamed content with its hash recorded in the same update. The hash is only known once all
+// the content was read, so the content is either uploaded before it's saved with the hash, or hashed and rewound.
+func streamVerified(ctx context.Context, files ItemsFilesAPI, item Item, id string, name string, content io.Reader,
+	save func(files fileUploader, item Item, uploadID string) (Item, error),
+	stream func(files ItemsFilesAPI, item Item, content io.Reader) (Item, error)) (Item, error) {
+	h := sha256.New()
+	if uploader, ok := files.(fileUploader); ok {
+		uploadID, err := uploader.upload(ctx, io.TeeReader(content, h))
+		if err != nil {
+			return Item{}, err
+		}
+		setFileHash(&item.Sections, &item.Fields, id, name, hex.EncodeToString(h.Sum(nil)))
+		updated, err := save(uploader, item, uploadID)
+		if err != nil {
+			return Item{}, errors.Join(err, uploader.abortUpload(context.WithoutCancel(ctx), uploadID))
+		}
+		return updated, nil
+	}
+
+	seeker, ok := content.(io.ReadSeeker)
+	if !ok {
+		return Item{}, fmt.Errorf("content of file %q must implement io.Seeker to be hashed before it's saved", name)
+	}
+	start, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return Item{}, err
+	}
+	if _, err := io.Copy(h, seeker); err != nil {
+		return Item{}, err
+	}
+	if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+		return Item{}, err
+	}
+	setFileHash(&item.Sections, &item.Fields, id, name, hex.EncodeToString(h.Sum(nil)))
+	return stream(files, item, seeker)
+}
+
+// integrityFilesSource is the Files API of clients created with WithFileIntegrity. It records the hashes of the
+// files it saves and verifies the content it reads, fetching the item to find its recorded hash.
+type integrityFilesSource struct {
+	files ItemsFilesAPI
+	items *ItemsSource
+}
+
+func (i integrityFilesSource) Attach(ctx context.Context, item Item, fileParams FileCreateParams) (Item, error) {
+	return AttachFileVerified(ctx, i.files, item, fileParams)
+}
+
+func (i integrityFilesSource) Read(ctx context.Context, vaultID string, itemID string, attr FileAttributes) ([]byte, error) {
+	item, err := i.items.Get(ctx, vaultID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	return ReadFileVerified(ctx, i.files, item, attr)
+}
+
+func (i integrityFilesSource) Delete(ctx context.Context, item Item, sectionID string, fieldID string) (Item, error) {
+	return DeleteFileVerified(ctx, i.files, item, sectionID, fieldID)
+}
+
+func (i integrityFilesSource) ReplaceDocument(ctx context.Context, item Item, docParams DocumentCreateParams) (Item, error) {
+	return ReplaceDocumentVerified(ctx, i.files, item, docParams)
+}
+
+func (i integrityFilesSource) OpenFile(ctx context.Context, vaultID string, itemID string, attr FileAttributes) (io.ReadCloser, error) {
+	item, err := i.items.Get(ctx, vaultID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	return OpenFileVerified(ctx, i.files, item, attr)
+}
+
+func (i integrityFilesSource) AttachStream(ctx context.Context, item Item, fileParams FileUploadParams, content io.Reader) (Item, error) {
+	return AttachStreamVerified(ctx, i.files, item, fileParams, content)
+}
+
+func (i integrityFilesSource) ReplaceDocumentStream(ctx context.Context, item Item, name string, content io.Reader) (Item, error) {
+	return ReplaceDocumentStreamVerified(ctx, i.files, item, name, content)
+}
+
+// unwrapIntegrity returns the Files API wrapped by a client created with WithFileIntegrity, so that the helpers
+// taking the item don't fetch it again.
+func unwrapIntegrity(files ItemsFilesAPI) ItemsFilesAPI {
+	if i, ok := files.(integrityFilesSource); ok {
+		return i.files
+	}
+	return files
+}
+
+// fileHashFieldID returns the ID of the field holding the hash of one of the item's files.
+func fileHashFieldID(item Item, attr FileAttributes) (string, bool) {
+	if item.Document != nil && item.Document.ID == attr.ID {
+		return documentHashFieldID, true
+	}
+	i := slices.IndexFunc(item.Files, func(f ItemFile) bool { return f.Attributes.ID == attr.ID })
+	if i < 0 {
+		return "", false
+	}
+	return fieldFileHashID(item.Files[i].SectionID, item.Files[i].FieldID), true
+}
+
+// fieldFileHashID returns the ID of the field holding the hash of the file at the given section and field.
+func fieldFileHashID(sectionID string, fieldID string) string {
+	return "sha256." + sectionID + "." + fieldID
+}
+
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// setFileHash records a hash in copies of the sections and fields, leaving those of the caller's item untouched.
+func setFileHash(sections *[]ItemSection, fields *[]ItemField, id string, name string, hash string) {
+	*sections, *fields = slices.Clone(*sections), slices.Clone(*fields)
+	if !slices.ContainsFunc(*sections, func(s ItemSection) bool { return s.ID == fileIntegritySectionID }) {
+		*sections = append(*sections, ItemSection{ID: fileIntegritySectionID, Title: fileIntegritySectionTitle})
+	}
+	sectionID := fileIntegritySectionID
+	*fields = upsert(*fields, ItemField{
+		ID:        id,
+		Title:     name,
+		SectionID: &sectionID,
+		FieldType: ItemFieldTypeConcealed,
+		Value:     hash,
+	}, func(f ItemField) bool {
+		return IsFileHashField(f) && f.ID == id
+	})
+}
+
+// removeFileHash removes a recorded hash, and the integrity section once it holds no hashes, from copies of the
+// sections and fields, reporting whether there was one.
+func removeFileHash(sections *[]ItemSection, fields *[]ItemField, id string) bool {
+	*sections, *fields = slices.Clone(*sections), slices.Clone(*fields)
+	n := len(*fields)
+	*fields = slices.DeleteFunc(*fields, func(f ItemField) bool { return IsFileHashField(f) && f.ID == id })
+	if !slices.ContainsFunc(*fields, IsFileHashField) {
+		*sections = slices.DeleteFunc(*sections, func(s ItemSection) bool { return s.ID == fileIntegritySectionID })
+	}
+	return len(*fields) != n
+}
+
+// IsFileHashField reports whether a field holds the recorded hash of a file.
+func IsFileHashField(field ItemField) bool {
+	return field.SectionID != nil && *field.SectionID == fileIntegritySectionID
+}
@@ -0,0 +1,225 @@
+package onepassword
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/1password/onepassword-sdk-go/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFilesCore serves a single item and the content of its files.
+type fakeFilesCore struct {
+	item    Item
+	content map[string][]byte
+	upload  []byte
+	// Fail attaching finished uploads, and record the uploads that were aborted
+	failAttachUpload bool
+	aborted          []string
+}
+
+func (c *fakeFilesCore) InitClient(ctx context.Context, config []byte) ([]byte, error) {
+	return []byte("1"), nil
+}
+
+func (c *fakeFilesCore) Invoke(ctx context.Context, invokeConfig []byte) ([]byte, error) {
+	name, params, err := parseInvocation(invokeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	switch name {
+	case "ItemsGet":
+		return json.Marshal(c.item)
+	case "ItemsFilesAttach":
+		var file FileCreateParams
+		if err := errors.Join(json.Unmarshal(params["item"], &c.item), json.Unmarshal(params["file_params"], &file)); err != nil {
+			return nil, err
+		}
+		c.attach(file.Name, file.SectionID, file.FieldID, file.Content)
+		return json.Marshal(c.item)
+	case "ItemsFilesDelete":
+		var sectionID, fieldID string
+		if err := errors.Join(json.Unmarshal(params["item"], &c.item), json.Unmarshal(params["section_id"], &sectionID), json.Unmarshal(params["field_id"], &fieldID)); err != nil {
+			return nil, err
+		}
+		c.item.Files = slices.DeleteFunc(c.item.Files, func(f ItemFile) bool { return f.SectionID == sectionID && f.FieldID == fieldID })
+		return json.Marshal(c.item)
+	case "ItemsFilesBeginUpload":
+		c.upload = nil
+		return json.Marshal("upload1")
+	case "ItemsFilesUploadChunk":
+		var chunk []byte
+		if err := json.Unmarshal(params["chunk"], &chunk); err != nil {
+			return nil, err
+		}
+		c.upload = append(c.upload, chunk...)
+		return []byte("null"), nil
+	case "ItemsFilesAttachUpload":
+		if c.failAttachUpload {
+			return nil, errors.New(`{"name":"","message":"item not found"}`)
+		}
+		var file FileUploadParams
+		if err := errors.Join(json.Unmarshal(params["item"], &c.item), json.Unmarshal(params["file_params"], &file)); err != nil {
+			return nil, err
+		}
+		c.attach(file.Name, file.SectionID, file.FieldID, c.upload)
+		return json.Marshal(c.item)
+	case "ItemsFilesReplaceDocumentUpload":
+		if c.failAttachUpload {
+			return nil, errors.New(`{"name":"","message":"item not found"}`)
+		}
+		var name string
+		if err := errors.Join(json.Unmarshal(params["item"], &c.item), json.Unmarshal(params["name"], &name)); err != nil {
+			return nil, err
+		}
+		c.item.Document = &FileAttributes{ID: "document1", Name: name, Size: uint32(len(c.upload))}
+		c.content[c.item.Document.ID] = c.upload
+		return json.Marshal(c.item)
+	case "ItemsFilesAbortUpload":
+		var uploadID string
+		if err := json.Unmarshal(params["upload_id"], &uploadID); err != nil {
+			return nil, err
+		}
+		c.aborted = append(c.aborted, uploadID)
+		return []byte("null"), nil
+	case "ItemsFilesRead":
+		var attr FileAttributes
+		if err := json.Unmarshal(params["attr"], &attr); err != nil {
+			return nil, err
+		}
+		return json.Marshal(c.content[attr.ID])
+	case "ItemsFilesReadChunk":
+		var attr FileAttributes
+		var offset, length uint64
+		if err := errors.Join(json.Unmarshal(params["attr"], &attr), json.Unmarshal(params["offset"], &offset), json.Unmarshal(params["length"], &length)); err != nil {
+			return nil, err
+		}
+		content := c.content[attr.ID]
+		return json.Marshal(content[min(offset, uint64(len(content))):min(offset+length, uint64(len(content)))])
+	}
+	return nil, errors.New("unexpected invocation " + name)
+}
+
+func (c *fakeFilesCore) attach(name string, sectionID string, fieldID string, content []byte) {
+	attr := FileAttributes{ID: fmt.Sprintf("file%d", len(c.item.Files)+1), Name: name, Size: uint32(len(content))}
+	c.item.Files = append(c.item.Files, ItemFile{Attributes: attr, SectionID: sectionID, FieldID: fieldID})
+	c.content[attr.ID] = content
+}
+
+func (c *fakeFilesCore) ReleaseClient(clientID []byte) {}
+
+// keystoreHash is the SHA-256 hash of "keystore".
+const keystoreHash = "284aaf4da604624b89af5327fadfd2c05bdb818ae8222755e2649dd7a223d244"
+
+func TestFileIntegrity(t *testing.T) {
+	ctx := context.Background()
+	core := &fakeFilesCore{item: Item{ID: "item1", VaultID: "vault1"}, content: map[string][]byte{}}
+	client := Client{}
+	require.NoError(t, WithFileIntegrity()(&client))
+	c, err := initClient(ctx, internal.CoreWrapper{InnerCore: core}, client)
+	require.NoError(t, err)
+	files := c.Items().Files()
+
+	item, err := files.Attach(ctx, core.item, FileCreateParams{Name: "keystore.jks", Content: []byte("keystore"), SectionID: "keys", FieldID: "keystore"})
+	require.NoError(t, err)
+	require.Len(t, item.Files, 1)
+	hash, ok := FileHash(item, item.Files[0].Attributes)
+	require.True(t, ok, "the hash is saved with the file")
+	assert.Equal(t, keystoreHash, hash)
+	i := slices.IndexFunc(item.Fields, IsFileHashField)
+	assert.Equal(t, ItemFieldTypeConcealed, item.Fields[i].FieldType)
+
+	content, err := files.Read(ctx, "vault1", "item1", item.Files[0].Attributes)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("keystore"), content)
+
+	core.content["file1"] = []byte("tampered")
+	_, err = files.Read(ctx, "vault1", "item1", item.Files[0].Attributes)
+	var integrityErr *FileIntegrityError
+	require.ErrorAs(t, err, &integrityErr)
+	assert.Equal(t, keystoreHash, integrityErr.Expected)
+
+	r, err := files.OpenFile(ctx, "vault1", "item1", item.Files[0].Attributes)
+	require.NoError(t, err)
+	_, err = io.ReadAll(r)
+	require.ErrorAs(t, err, &integrityErr)
+
+	item, err = files.Delete(ctx, item, "keys", "keystore")
+	require.NoError(t, err)
+	assert.Empty(t, item.Files)
+	assert.Empty(t, item.Fields, "the hash is removed with the file")
+	assert.Empty(t, item.Sections)
+}
+
+func TestFileIntegrityHelpers(t *testing.T) {
+	ctx := context.Background()
+	core := &fakeFilesCore{item: Item{ID: "item1", VaultID: "vault1"}, content: map[string][]byte{}}
+	files := NewItemsFilesSource(&internal.InnerClient{Core: internal.CoreWrapper{InnerCore: core}})
+
+	item, err := AttachFileVerified(ctx, files, core.item, FileCreateParams{Name: "keystore.jks", Content: []byte("keystore"), SectionID: "keys", FieldID: "keystore"})
+	require.NoError(t, err)
+	content, err := ReadFileVerified(ctx, files, item, item.Files[0].Attributes)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("keystore"), content)
+
+	core.content["file1"] = []byte("tampered")
+	_, err = ReadFileVerified(ctx, files, item, item.Files[0].Attributes)
+	var integrityErr *FileIntegrityError
+	require.ErrorAs(t, err, &integrityErr)
+	content, err = files.Read(ctx, "vault1", "item1", item.Files[0].Attributes)
+	require.NoError(t, err, "Read only verifies content on clients created with WithFileIntegrity")
+	assert.Equal(t, []byte("tampered"), content)
+
+	// Files APIs implemented outside of the SDK get content that can be hashed before it's attached.
+	outside := struct{ ItemsFilesAPI }{files}
+	item, err = AttachStreamVerified(ctx, outside, item, FileUploadParams{Name: "backup.jks", SectionID: "keys", FieldID: "backup"}, strings.NewReader("keystore"))
+	require.NoError(t, err)
+	require.Len(t, item.Files, 2)
+	hash, ok := FileHash(item, item.Files[1].Attributes)
+	require.True(t, ok)
+	assert.Equal(t, keystoreHash, hash)
+	_, err = AttachStreamVerified(ctx, outside, item, FileUploadParams{Name: "other.jks", SectionID: "keys", FieldID: "other"}, io.LimitReader(strings.NewReader("keystore"), 8))
+	assert.ErrorContains(t, err, "must implement io.Seeker")
+}
+
+func TestFileHashesOnCreateAndCopy(t *testing.T) {
+	ctx := context.Background()
+	params := WithFileHashes(ItemCreateParams{
+		Title: "Signing key",
+		Files: []FileCreateParams{{Name: "keystore.jks", Content: []byte("keystore"), SectionID: "keys", FieldID: "keystore"}},
+	})
+	require.Len(t, params.Fields, 1)
+	assert.Equal(t, keystoreHash, params.Fields[0].Value)
+
+	// The hashes are keyed by the location of the files, so they hold for the created item and for its copies.
+	item := Item{
+		ID:       "item1",
+		VaultID:  "vault1",
+		Sections: params.Sections,
+		Fields:   params.Fields,
+		Files:    []ItemFile{{Attributes: FileAttributes{ID: "file1", Name: "keystore.jks", Size: 8}, SectionID: "keys", FieldID: "keystore"}},
+	}
+	core := &fakeFilesCore{item: item, content: map[string][]byte{"file1": []byte("keystore")}}
+	files := NewItemsFilesSource(&internal.InnerClient{Core: internal.CoreWrapper{InnerCore: core}})
+	copied, err := ItemCreateParamsFromItem(ctx, item, func(ctx context.Context, attr FileAttributes) ([]byte, error) {
+		return ReadFileVerified(ctx, files, item, attr)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, params.Sections, copied.Sections)
+	assert.Equal(t, params.Fields, copied.Fields)
+
+	core.content["file1"] = []byte("tampered")
+	_, err = ItemCreateParamsFromItem(ctx, item, func(ctx context.Context, attr FileAttributes) ([]byte, error) {
+		return ReadFileVerified(ctx, files, item, attr)
+	})
+	var integrityErr *FileIntegrityError
+	require.ErrorAs(t, err, &integrityErr)
+}
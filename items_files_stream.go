@@ -0,0 +1,207 @@
+package onepassword
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+)
+
+// fileChunkSize is the amount of file content transferred per invocation. Chunks are base64 encoded in the
+// serialized invocation, so they must stay well below internal.MessageLimit.
+const fileChunkSize = 8 * 1024 * 1024
+
+// The location and name of a file attached from an upload.
+type FileUploadParams struct {
+	// The name of the file
+	Name string `json:"name"`
+	// The section id where the file should be stored
+	SectionID string `json:"sectionId"`
+	// The field id where the file should be stored
+	FieldID string `json:"fieldId"`
+}
+
+// OpenFile opens a file of the Item for reading. Its content is downloaded in chunks as it is read,
+// so files larger than the size limit of a single invocation can be read without holding them in memory.
+func (i ItemsFilesSource) OpenFile(ctx context.Context, vaultID string, itemID string, attr FileAttributes) (io.ReadCloser, error) {
+	return &fileReader{ctx: ctx, files: i, vaultID: vaultID, itemID: itemID, attr: attr}, nil
+}
+
+type fileReader struct {
+	ctx     context.Context
+	files   ItemsFilesSource
+	vaultID string
+	itemID  string
+	attr    FileAttributes
+	offset  uint64
+	chunk   []byte
+	closed  bool
+}
+
+func (r *fileReader) Read(p []byte) (int, error) {
+	if r.closed {
+		return 0, fs.ErrClosed
+	}
+	if len(r.chunk) == 0 {
+		if r.offset >= uint64(r.attr.Size) {
+			return 0, io.EOF
+		}
+		chunk, err := r.files.readChunk(r.ctx, r.vaultID, r.itemID, r.attr, r.offset, min(fileChunkSize, uint64(r.attr.Size)-r.offset))
+		if err != nil {
+			return 0, err
+		}
+		if len(chunk) == 0 {
+			return 0, io.ErrUnexpectedEOF
+		}
+		r.chunk = chunk
+		r.offset += uint64(len(chunk))
+	}
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+	return n, nil
+}
+
+func (r *fileReader) Close() error {
+	r.closed = true
+	r.chunk = nil
+	return nil
+}
+
+// AttachStream attaches a file to the Item, uploading its content from a reader in chunks.
+func (i ItemsFilesSource) AttachStream(ctx context.Context, item Item, fileParams FileUploadParams, content io.Reader) (Item, error) {
+	uploadID, err := i.upload(ctx, content)
+	if err != nil {
+		return Item{}, err
+	}
+	updated, err := i.attachUpload(ctx, item, uploadID, fileParams)
+	if err != nil {
+		return Item{}, errors.Join(err, i.abortUpload(context.WithoutCancel(ctx), uploadID))
+	}
+	return updated, nil
+}
+
+// ReplaceDocumentStream replaces the document file within a document item, uploading its content from a reader in chunks.
+func (i ItemsFilesSource) ReplaceDocumentStream(ctx context.Context, item Item, name string, content io.Reader) (Item, error) {
+	uploadID, err := i.upload(ctx, content)
+	if err != nil {
+		return Item{}, err
+	}
+	updated, err := i.replaceDocumentUpload(ctx, item, uploadID, name)
+	if err != nil {
+		return Item{}, errors.Join(err, i.abortUpload(context.WithoutCancel(ctx), uploadID))
+	}
+	return updated, nil
+}
+
+// upload sends the content of a reader to the core in chunks, returning the ID of the finished upload.
+// The upload is discarded if reading or sending a chunk fails; callers must discard it as well if it can't be attached.
+func (i ItemsFilesSource) upload(ctx context.Context, content io.Reader) (string, error) {
+	uploadID, err := i.beginUpload(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, fileChunkSize)
+	for {
+		n, err := io.ReadFull(content, buf)
+		if n > 0 {
+			if uploadErr := i.uploadChunk(ctx, uploadID, buf[:n]); uploadErr != nil {
+				return "", errors.Join(uploadErr, i.abortUpload(context.WithoutCancel(ctx), uploadID))
+			}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return uploadID, nil
+		}
+		if err != nil {
+			return "", errors.Join(err, i.abortUpload(context.WithoutCancel(ctx), uploadID))
+		}
+	}
+}
+
+// Read a chunk of a file's content, starting at the given offset.
+func (i ItemsFilesSource) readChunk(ctx context.Context, vaultID string, itemID string, attr FileAttributes, offset uint64, length uint64) ([]byte, error) {
+	resultString, err := clientInvoke(ctx, i.InnerClient, "ItemsFilesReadChunk", map[string]interface{}{
+		"vault_id": vaultID,
+		"item_id":  itemID,
+		"attr":     attr,
+		"offset":   offset,
+		"length":   length,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var result []byte
+	err = json.Unmarshal([]byte(*resultString), &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Start a chunked file upload, returning its ID.
+func (i ItemsFilesSource) beginUpload(ctx context.Context) (string, error) {
+	resultString, err := clientInvoke(ctx, i.InnerClient, "ItemsFilesBeginUpload", map[string]interface{}{})
+	if err != nil {
+		return "", err
+	}
+	var result string
+	err = json.Unmarshal([]byte(*resultString), &result)
+	if err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+// Append a chunk of content to a file upload.
+func (i ItemsFilesSource) uploadChunk(ctx context.Context, uploadID string, chunk []byte) error {
+	_, err := clientInvoke(ctx, i.InnerClient, "ItemsFilesUploadChunk", map[string]interface{}{
+		"upload_id": uploadID,
+		"chunk":     chunk,
+	})
+	return err
+}
+
+// Discard a file upload that won't be attached.
+func (i ItemsFilesSource) abortUpload(ctx context.Context, uploadID string) error {
+	_, err := clientInvoke(ctx, i.InnerClient, "ItemsFilesAbortUpload", map[string]interface{}{
+		"upload_id": uploadID,
+	})
+	return err
+}
+
+// Attach the content of a finished upload to the Item.
+func (i ItemsFilesSource) attachUpload(ctx context.Context, item Item, uploadID string, fileParams FileUploadParams) (Item, error) {
+	resultString, err := clientInvoke(ctx, i.InnerClient, "ItemsFilesAttachUpload", map[string]interface{}{
+		"item":        item,
+		"upload_id":   uploadID,
+		"file_params": fileParams,
+	})
+	if err != nil {
+		return Item{}, err
+	}
+	var result Item
+	err = json.Unmarshal([]byte(*resultString), &result)
+	if err != nil {
+		return Item{}, err
+	}
+	return result, nil
+}
+
+// Replace the document file within a document item with the content of a finished upload.
+func (i ItemsFilesSource) replaceDocumentUpload(ctx context.Context, item Item, uploadID string, name string) (Item, error) {
+	resultString, err := clientInvoke(ctx, i.InnerClient, "ItemsFilesReplaceDocumentUpload", map[string]interface{}{
+		"item":      item,
+		"upload_id": uploadID,
+		"name":      name,
+	})
+	if err != nil {
+		return Item{}, err
+	}
+	var result Item
+	err = json.Unmarshal([]byte(*resultString), &result)
+	if err != nil {
+		return Item{}, err
+	}
+	return result, nil
+}
@@ -0,0 +1,51 @@
+package onepassword
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/1password/onepassword-sdk-go/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachStreamAbortsUploadWhenAttachFails(t *testing.T) {
+	ctx := context.Background()
+	core := &fakeFilesCore{item: Item{ID: "item1", VaultID: "vault1"}, content: map[string][]byte{}, failAttachUpload: true}
+	files := NewItemsFilesSource(&internal.InnerClient{Core: internal.CoreWrapper{InnerCore: core}})
+
+	_, err := files.AttachStream(ctx, core.item, FileUploadParams{Name: "dump.sql", SectionID: "backups", FieldID: "dump"}, strings.NewReader("SELECT 1;"))
+	require.EqualError(t, err, "item not found")
+	assert.Equal(t, []string{"upload1"}, core.aborted)
+
+	_, err = files.ReplaceDocumentStream(ctx, core.item, "dump.sql", strings.NewReader("SELECT 1;"))
+	require.EqualError(t, err, "item not found")
+	assert.Equal(t, []string{"upload1", "upload1"}, core.aborted)
+}
+
+func TestStreamVerifiedRecordsHashWithUpload(t *testing.T) {
+	ctx := context.Background()
+	core := &fakeFilesCore{item: Item{ID: "item1", VaultID: "vault1"}, content: map[string][]byte{}}
+	files := NewItemsFilesSource(&internal.InnerClient{Core: internal.CoreWrapper{InnerCore: core}})
+
+	item, err := AttachStreamVerified(ctx, files, core.item, FileUploadParams{Name: "keystore.jks", SectionID: "keys", FieldID: "keystore"}, io.LimitReader(strings.NewReader("keystore"), 8))
+	require.NoError(t, err)
+	require.Len(t, item.Files, 1)
+	hash, ok := FileHash(item, item.Files[0].Attributes)
+	require.True(t, ok)
+	assert.Equal(t, keystoreHash, hash)
+
+	item, err = ReplaceDocumentStreamVerified(ctx, files, item, "keystore.jks", strings.NewReader("keystore"))
+	require.NoError(t, err)
+	hash, ok = FileHash(item, *item.Document)
+	require.True(t, ok)
+	assert.Equal(t, keystoreHash, hash)
+
+	r, err := OpenFileVerified(ctx, files, item, *item.Document)
+	require.NoError(t, err)
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("keystore"), content)
+}
@@ -0,0 +1,75 @@
+package onepassword
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Represents a previous version of an item.
+type ItemHistoryEntry struct {
+	// The version number of the item at this point in its history
+	Version uint32 `json:"version"`
+	// The time this version of the item was saved
+	UpdatedAt time.Time `json:"updatedAt"`
+	// The item as it was at this version
+	Item Item `json:"item"`
+}
+
+// Restore an archived item.
+func (i ItemsSource) Restore(ctx context.Context, vaultID string, itemID string) error {
+	_, err := clientInvoke(ctx, i.InnerClient, "ItemsRestore", map[string]interface{}{
+		"vault_id": vaultID,
+		"item_id":  itemID,
+	})
+	return err
+}
+
+// List the previous versions of an item, newest first.
+func (i ItemsSource) History(ctx context.Context, vaultID string, itemID string) ([]ItemHistoryEntry, error) {
+	resultString, err := clientInvoke(ctx, i.InnerClient, "ItemsHistory", map[string]interface{}{
+		"vault_id": vaultID,
+		"item_id":  itemID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var result []ItemHistoryEntry
+	err = json.Unmarshal([]byte(*resultString), &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Rollback an item to a previous version from its history.
+// The title, fields, sections, notes, tags and websites of that version are written as a new version of the item.
+// Files and the document are left as they currently are, since their previous content may no longer be stored.
+func (i ItemsSource) Rollback(ctx context.Context, vaultID string, itemID string, version uint32) (Item, error) {
+	history, err := i.History(ctx, vaultID, itemID)
+	if err != nil {
+		return Item{}, err
+	}
+
+	var previous *Item
+	for _, entry := range history {
+		if entry.Version == version {
+			previous = &entry.Item
+			break
+		}
+	}
+	if previous == nil {
+		return Item{}, fmt.Errorf("version %d of item %s not found in its history", version, itemID)
+	}
+
+	return i.Update(ctx, vaultID, itemID, func(item *Item) error {
+		item.Title = previous.Title
+		item.Fields = previous.Fields
+		item.Sections = previous.Sections
+		item.Notes = previous.Notes
+		item.Tags = previous.Tags
+		item.Websites = previous.Websites
+		return nil
+	})
+}
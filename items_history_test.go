@@ -0,0 +1,145 @@
+package onepassword
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/1password/onepassword-sdk-go/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeHistoryCore serves a single item with its history. The first conflicts updates of the item fail with the error
+// the core returns when the item was changed concurrently.
+type fakeHistoryCore struct {
+	item      Item
+	history   []ItemHistoryEntry
+	conflicts int
+	puts      int
+	restored  map[string]json.RawMessage
+}
+
+func (c *fakeHistoryCore) InitClient(ctx context.Context, config []byte) ([]byte, error) {
+	return []byte("1"), nil
+}
+
+func (c *fakeHistoryCore) Invoke(ctx context.Context, invokeConfig []byte) ([]byte, error) {
+	name, params, err := parseInvocation(invokeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	switch name {
+	case "ItemsGet":
+		return json.Marshal(c.item)
+	case "ItemsHistory":
+		return json.Marshal(c.history)
+	case "ItemsRestore":
+		c.restored = params
+		return []byte("null"), nil
+	case "ItemsPut":
+		c.puts++
+		var item Item
+		if err := json.Unmarshal(params["item"], &item); err != nil {
+			return nil, err
+		}
+		if c.conflicts > 0 {
+			c.conflicts--
+			c.item.Version++
+			c.item.Tags = append(c.item.Tags, "changed")
+			return nil, errors.New(`{"name":"itemStatusIncorrectItemVersion","message":"the item was changed"}`)
+		}
+		item.Version++
+		c.item = item
+		return json.Marshal(c.item)
+	}
+	return nil, errors.New("unexpected invocation " + name)
+}
+
+func (c *fakeHistoryCore) ReleaseClient(clientID []byte) {}
+
+func newFakeHistoryCore() *fakeHistoryCore {
+	return &fakeHistoryCore{
+		item: Item{
+			ID:       "item1",
+			VaultID:  "vault1",
+			Title:    "Current",
+			Category: ItemCategoryLogin,
+			Fields:   []ItemField{{ID: "password", Title: "password", FieldType: ItemFieldTypeConcealed, Value: "current"}},
+			Files:    []ItemFile{{Attributes: FileAttributes{ID: "f1", Name: "key.pem"}, SectionID: "s", FieldID: "f"}},
+			Version:  3,
+		},
+		history: []ItemHistoryEntry{
+			{Version: 2, UpdatedAt: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Item: Item{
+				ID:      "item1",
+				VaultID: "vault1",
+				Title:   "Previous",
+				Fields:  []ItemField{{ID: "password", Title: "password", FieldType: ItemFieldTypeConcealed, Value: "previous"}},
+				Notes:   "rotated",
+				Tags:    []string{"old"},
+				Version: 2,
+			}},
+			{Version: 1, UpdatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Item: Item{ID: "item1", VaultID: "vault1", Title: "First", Version: 1}},
+		},
+	}
+}
+
+func TestRestore(t *testing.T) {
+	core := newFakeHistoryCore()
+	items := NewItemsSource(&internal.InnerClient{Core: internal.CoreWrapper{InnerCore: core}})
+
+	require.NoError(t, items.Restore(context.Background(), "vault1", "item1"))
+	assert.JSONEq(t, `"vault1"`, string(core.restored["vault_id"]))
+	assert.JSONEq(t, `"item1"`, string(core.restored["item_id"]))
+}
+
+func TestHistory(t *testing.T) {
+	core := newFakeHistoryCore()
+	items := NewItemsSource(&internal.InnerClient{Core: internal.CoreWrapper{InnerCore: core}})
+
+	history, err := items.History(context.Background(), "vault1", "item1")
+	require.NoError(t, err)
+	assert.Equal(t, core.history, history)
+}
+
+func TestRollback(t *testing.T) {
+	ctx := context.Background()
+	core := newFakeHistoryCore()
+	items := NewItemsSource(&internal.InnerClient{Core: internal.CoreWrapper{InnerCore: core}})
+
+	item, err := items.Rollback(ctx, "vault1", "item1", 2)
+	require.NoError(t, err)
+	assert.Equal(t, "Previous", item.Title)
+	assert.Equal(t, "previous", item.Fields[0].Value)
+	assert.Equal(t, "rotated", item.Notes)
+	assert.Equal(t, []string{"old"}, item.Tags)
+	assert.Equal(t, core.item.Files, item.Files, "files are left as they currently are")
+	assert.Equal(t, uint32(4), item.Version, "the rollback is saved as a new version")
+	assert.Equal(t, 1, core.puts)
+}
+
+func TestRollbackUnknownVersion(t *testing.T) {
+	core := newFakeHistoryCore()
+	items := NewItemsSource(&internal.InnerClient{Core: internal.CoreWrapper{InnerCore: core}})
+
+	_, err := items.Rollback(context.Background(), "vault1", "item1", 7)
+	assert.EqualError(t, err, "version 7 of item item1 not found in its history")
+	assert.Equal(t, 0, core.puts)
+	assert.Equal(t, "Current", core.item.Title)
+}
+
+func TestRollbackRetriesConflicts(t *testing.T) {
+	core := newFakeHistoryCore()
+	core.conflicts = 1
+	items := NewItemsSource(&internal.InnerClient{Core: internal.CoreWrapper{InnerCore: core}})
+
+	item, err := items.Rollback(context.Background(), "vault1", "item1", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "First", item.Title)
+	assert.Empty(t, item.Tags, "the rollback replaces the tags of the concurrent change")
+	assert.Equal(t, uint32(5), item.Version, "the retry is saved on top of the concurrent change")
+	assert.Equal(t, 2, core.puts)
+}
@@ -0,0 +1,113 @@
+package onepassword
+
+import (
+	"context"
+	"fmt"
+)
+
+// ItemMoveError is returned by Items().Move when the item was copied to the destination vault but the original couldn't be deleted.
+// The item then exists in both vaults.
+type ItemMoveError struct {
+	// The copy of the item in the destination vault
+	Item Item
+	// The ID of the vault holding the original item
+	SourceVaultID string
+	// The ID of the original item
+	SourceItemID string
+	// The error returned when deleting the original item
+	Err error
+}
+
+func (e *ItemMoveError) Error() string {
+	return fmt.Sprintf("item %s was copied to vault %s as %s, but deleting the original from vault %s failed: %v", e.SourceItemID, e.Item.VaultID, e.Item.ID, e.SourceVaultID, e.Err)
+}
+
+func (e *ItemMoveError) Unwrap() error {
+	return e.Err
+}
+
+// Copy an item into another vault, including its fields, sections, notes, tags, websites, files and document.
+func (i ItemsSource) Copy(ctx context.Context, vaultID string, itemID string, destinationVaultID string) (Item, error) {
+	item, err := i.Get(ctx, vaultID, itemID)
+	if err != nil {
+		return Item{}, err
+	}
+	params, err := ItemCreateParamsFromItem(ctx, item, func(ctx context.Context, attr FileAttributes) ([]byte, error) {
+		return ReadFileVerified(ctx, i.FilesAPI, item, attr)
+	})
+	if err != nil {
+		return Item{}, err
+	}
+	params.VaultID = destinationVaultID
+	return i.Create(ctx, params)
+}
+
+// Move an item into another vault by copying it and deleting the original.
+// If the copy was created but the original couldn't be deleted, an *ItemMoveError holding the copy is returned.
+func (i ItemsSource) Move(ctx context.Context, vaultID string, itemID string, destinationVaultID string) (Item, error) {
+	moved, err := i.Copy(ctx, vaultID, itemID, destinationVaultID)
+	if err != nil {
+		return Item{}, err
+	}
+	if err := i.Delete(ctx, vaultID, itemID); err != nil {
+		return moved, &ItemMoveError{
+			Item:          moved,
+			SourceVaultID: vaultID,
+			SourceItemID:  itemID,
+			Err:           err,
+		}
+	}
+	return moved, nil
+}
+
+// ItemCreateParamsFromItem returns the parameters to create a copy of the given item in the same vault,
+// reading the content of its files and document with readFile. Content hashes recorded for the files are carried
+// over to the copy.
+func ItemCreateParamsFromItem(ctx context.Context, item Item, readFile func(ctx context.Context, attr FileAttributes) ([]byte, error)) (ItemCreateParams, error) {
+	params := ItemCreateParams{
+		Category: item.Category,
+		VaultID:  item.VaultID,
+		Title:    item.Title,
+		Sections: item.Sections,
+		Tags:     item.Tags,
+		Websites: item.Websites,
+	}
+	if item.Notes != "" {
+		notes := item.Notes
+		params.Notes = &notes
+	}
+
+	for _, field := range item.Fields {
+		// OTP codes and SSH key attributes are computed from the field value, only addresses must be carried over.
+		if field.Details != nil && field.Details.Type != ItemFieldDetailsTypeVariantAddress {
+			field.Details = nil
+		}
+		params.Fields = append(params.Fields, field)
+	}
+
+	for _, file := range item.Files {
+		content, err := readFile(ctx, file.Attributes)
+		if err != nil {
+			return ItemCreateParams{}, fmt.Errorf("error reading file %q of item %s: %w", file.Attributes.Name, item.ID, err)
+		}
+		params.Files = append(params.Files, FileCreateParams{
+			Name:      file.Attributes.Name,
+			Content:   content,
+			SectionID: file.SectionID,
+			FieldID:   file.FieldID,
+		})
+	}
+
+	if item.Document != nil {
+		content, err := readFile(ctx, *item.Document)
+		if err != nil {
+			return ItemCreateParams{}, fmt.Errorf("error reading document %q of item %s: %w", item.Document.Name, item.ID, err)
+		}
+		params.Document = &DocumentCreateParams{
+			Name:    item.Document.Name,
+			Content: content,
+		}
+	}
+
+	return params, nil
+}
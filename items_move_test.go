@@ -0,0 +1,213 @@
+package onepassword
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/1password/onepassword-sdk-go/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVaultsCore stores items across vaults together with the content of their files, and records the items it
+// deleted. Deletes fail if failDelete is set.
+type fakeVaultsCore struct {
+	items      map[string]Item
+	content    map[string][]byte
+	created    []ItemCreateParams
+	deleted    []string
+	failDelete bool
+}
+
+func (c *fakeVaultsCore) InitClient(ctx context.Context, config []byte) ([]byte, error) {
+	return []byte("1"), nil
+}
+
+func (c *fakeVaultsCore) Invoke(ctx context.Context, invokeConfig []byte) ([]byte, error) {
+	name, params, err := parseInvocation(invokeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	switch name {
+	case "ItemsGet":
+		var vaultID, itemID string
+		if err := errors.Join(json.Unmarshal(params["vault_id"], &vaultID), json.Unmarshal(params["item_id"], &itemID)); err != nil {
+			return nil, err
+		}
+		item, ok := c.items[vaultID+"/"+itemID]
+		if !ok {
+			return nil, errors.New(`{"name":"","message":"item not found"}`)
+		}
+		return json.Marshal(item)
+	case "ItemsCreate":
+		var create ItemCreateParams
+		if err := json.Unmarshal(params["params"], &create); err != nil {
+			return nil, err
+		}
+		c.created = append(c.created, create)
+		item := Item{
+			ID:       fmt.Sprintf("copy%d", len(c.created)),
+			VaultID:  create.VaultID,
+			Title:    create.Title,
+			Category: create.Category,
+			Sections: create.Sections,
+			Fields:   create.Fields,
+			Tags:     create.Tags,
+			Websites: create.Websites,
+		}
+		if create.Notes != nil {
+			item.Notes = *create.Notes
+		}
+		for i, file := range create.Files {
+			attr := FileAttributes{ID: fmt.Sprintf("%s-file%d", item.ID, i), Name: file.Name}
+			item.Files = append(item.Files, ItemFile{Attributes: attr, SectionID: file.SectionID, FieldID: file.FieldID})
+			c.content[attr.ID] = file.Content
+		}
+		if create.Document != nil {
+			item.Document = &FileAttributes{ID: item.ID + "-document", Name: create.Document.Name}
+			c.content[item.Document.ID] = create.Document.Content
+		}
+		c.items[item.VaultID+"/"+item.ID] = item
+		return json.Marshal(item)
+	case "ItemsDelete":
+		if c.failDelete {
+			return nil, errors.New(`{"name":"","message":"permission denied"}`)
+		}
+		var vaultID, itemID string
+		if err := errors.Join(json.Unmarshal(params["vault_id"], &vaultID), json.Unmarshal(params["item_id"], &itemID)); err != nil {
+			return nil, err
+		}
+		delete(c.items, vaultID+"/"+itemID)
+		c.deleted = append(c.deleted, vaultID+"/"+itemID)
+		return []byte("null"), nil
+	case "ItemsFilesRead":
+		var attr FileAttributes
+		if err := json.Unmarshal(params["attr"], &attr); err != nil {
+			return nil, err
+		}
+		content, ok := c.content[attr.ID]
+		if !ok {
+			return nil, errors.New(`{"name":"","message":"file not found"}`)
+		}
+		return json.Marshal(content)
+	}
+	return nil, errors.New("unexpected invocation " + name)
+}
+
+func (c *fakeVaultsCore) ReleaseClient(clientID []byte) {}
+
+func newFakeVaultsItems(core *fakeVaultsCore) ItemsAPI {
+	return NewItemsSource(&internal.InnerClient{Core: internal.CoreWrapper{InnerCore: core}})
+}
+
+func TestCopy(t *testing.T) {
+	ctx := context.Background()
+	sectionID := "details"
+	original := Item{
+		ID:       "item1",
+		VaultID:  "source",
+		Title:    "Database",
+		Category: ItemCategoryLogin,
+		Sections: []ItemSection{{ID: sectionID, Title: "Details"}},
+		Fields: []ItemField{
+			{ID: "username", Title: "username", FieldType: ItemFieldTypeText, Value: "admin"},
+			{ID: "host", Title: "host", SectionID: &sectionID, FieldType: ItemFieldTypeText, Value: "db.internal"},
+		},
+		Notes:    "Primary database",
+		Tags:     []string{"prod"},
+		Websites: []Website{{URL: "https://db.internal", Label: "console", AutofillBehavior: AutofillBehaviorAnywhereOnWebsite}},
+	}
+	core := &fakeVaultsCore{items: map[string]Item{"source/item1": original}, content: map[string][]byte{}}
+	items := newFakeVaultsItems(core)
+
+	copied, err := items.Copy(ctx, "source", "item1", "destination")
+	require.NoError(t, err)
+	assert.Equal(t, "destination", copied.VaultID)
+	assert.NotEqual(t, original.ID, copied.ID)
+	assert.Equal(t, original.Title, copied.Title)
+	assert.Equal(t, original.Sections, copied.Sections)
+	assert.Equal(t, original.Fields, copied.Fields)
+	assert.Equal(t, original.Notes, copied.Notes)
+	assert.Equal(t, original.Tags, copied.Tags)
+	assert.Equal(t, original.Websites, copied.Websites)
+	assert.Contains(t, core.items, "source/item1", "Copy must keep the original")
+	assert.Empty(t, core.deleted)
+}
+
+func TestCopyWithFilesAndDocument(t *testing.T) {
+	ctx := context.Background()
+	core := &fakeVaultsCore{
+		items: map[string]Item{
+			"source/item1": {
+				ID:       "item1",
+				VaultID:  "source",
+				Title:    "Certificates",
+				Category: ItemCategoryServer,
+				Sections: []ItemSection{{ID: "tls", Title: "TLS"}},
+				Files: []ItemFile{
+					{Attributes: FileAttributes{ID: "f1", Name: "server.crt", Size: 4}, SectionID: "tls", FieldID: "cert"},
+					{Attributes: FileAttributes{ID: "f2", Name: "server.key", Size: 3}, SectionID: "tls", FieldID: "key"},
+				},
+			},
+			"source/item2": {
+				ID:       "item2",
+				VaultID:  "source",
+				Title:    "Runbook",
+				Category: ItemCategoryDocument,
+				Document: &FileAttributes{ID: "d1", Name: "runbook.pdf", Size: 3},
+			},
+		},
+		content: map[string][]byte{"f1": []byte("cert"), "f2": []byte("key"), "d1": []byte("pdf")},
+	}
+	items := newFakeVaultsItems(core)
+
+	copied, err := items.Copy(ctx, "source", "item1", "destination")
+	require.NoError(t, err)
+	require.Len(t, copied.Files, 2)
+	for i, name := range []string{"server.crt", "server.key"} {
+		file := copied.Files[i]
+		assert.Equal(t, name, file.Attributes.Name)
+		assert.Equal(t, "tls", file.SectionID)
+		assert.Equal(t, core.content[core.items["source/item1"].Files[i].Attributes.ID], core.content[file.Attributes.ID])
+	}
+
+	copied, err = items.Copy(ctx, "source", "item2", "destination")
+	require.NoError(t, err)
+	require.NotNil(t, copied.Document)
+	assert.Equal(t, "runbook.pdf", copied.Document.Name)
+	assert.Equal(t, []byte("pdf"), core.content[copied.Document.ID])
+
+	delete(core.content, "d1")
+	_, err = items.Copy(ctx, "source", "item2", "destination")
+	assert.ErrorContains(t, err, `error reading document "runbook.pdf" of item item2`)
+}
+
+func TestMove(t *testing.T) {
+	ctx := context.Background()
+	original := Item{ID: "item1", VaultID: "source", Title: "API key", Category: ItemCategoryAPICredentials}
+	core := &fakeVaultsCore{items: map[string]Item{"source/item1": original}, content: map[string][]byte{}}
+	items := newFakeVaultsItems(core)
+
+	moved, err := items.Move(ctx, "source", "item1", "destination")
+	require.NoError(t, err)
+	assert.Equal(t, "destination", moved.VaultID)
+	assert.Equal(t, []string{"source/item1"}, core.deleted)
+	assert.NotContains(t, core.items, "source/item1")
+
+	core.items["source/item1"] = original
+	core.failDelete = true
+	moved, err = items.Move(ctx, "source", "item1", "destination")
+	var moveErr *ItemMoveError
+	require.ErrorAs(t, err, &moveErr)
+	assert.Equal(t, moved, moveErr.Item)
+	assert.Equal(t, "destination", moveErr.Item.VaultID)
+	assert.Equal(t, "source", moveErr.SourceVaultID)
+	assert.Equal(t, "item1", moveErr.SourceItemID)
+	assert.ErrorContains(t, moveErr.Err, "permission denied")
+	assert.Contains(t, core.items, "source/item1", "the original is kept when it can't be deleted")
+	assert.Contains(t, core.items, "destination/"+moved.ID, "the copy is kept when the original can't be deleted")
+}
@@ -0,0 +1,377 @@
+package onepassword
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// maskedValue replaces the value of secret fields in an ItemDiff.
+const maskedValue = "********"
+
+// The type of change described by an ItemChange.
+type ItemChangeType string
+
+const (
+	ItemChangeTypeAdded   ItemChangeType = "added"
+	ItemChangeTypeRemoved ItemChangeType = "removed"
+	ItemChangeTypeChanged ItemChangeType = "changed"
+)
+
+// The part of an item an ItemChange applies to.
+type ItemChangeTarget string
+
+const (
+	ItemChangeTargetTitle    ItemChangeTarget = "title"
+	ItemChangeTargetNotes    ItemChangeTarget = "notes"
+	ItemChangeTargetSection  ItemChangeTarget = "section"
+	ItemChangeTargetField    ItemChangeTarget = "field"
+	ItemChangeTargetTag      ItemChangeTarget = "tag"
+	ItemChangeTargetWebsite  ItemChangeTarget = "website"
+	ItemChangeTargetFile     ItemChangeTarget = "file"
+	ItemChangeTargetDocument ItemChangeTarget = "document"
+)
+
+// Represents a single difference between two versions of an item.
+type ItemChange struct {
+	// The type of change
+	Type ItemChangeType
+	// The part of the item that changed
+	Target ItemChangeTarget
+	// The ID of the changed section, field or file, the changed tag or the URL of the changed website. Empty for the title, notes and document.
+	Key string
+	// A human readable name of the changed element, e.g. "Connection/password" for a field in the Connection section
+	Label string
+	// The previous value, masked for secret fields. Empty for added elements.
+	Old string
+	// The new value, masked for secret fields. Empty for removed elements.
+	New string
+	// Whether Old and New were masked because the field holds a secret: a concealed field, an SSH key,
+	// a one-time password seed or a credit card number
+	Concealed bool
+
+	oldValue string
+	newValue string
+}
+
+// Reveal returns the unmasked previous and new values of the change.
+func (c ItemChange) Reveal() (string, string) {
+	return c.oldValue, c.newValue
+}
+
+// Describes the differences between two versions of an item. Created by DiffItems.
+type ItemDiff struct {
+	// The individual changes, ordered by target
+	Changes []ItemChange
+
+	patch ItemPatch
+}
+
+// Empty reports whether the two items compared have no differences.
+func (d ItemDiff) Empty() bool {
+	return len(d.Changes) == 0
+}
+
+// Patch returns the partial update that turns the first item of the diff into the second.
+// File and document changes are reported in the diff, but are not part of the patch since their content isn't available.
+func (d ItemDiff) Patch() ItemPatch {
+	return d.patch
+}
+
+// String formats the diff for review, one change per line, with secret values masked.
+func (d ItemDiff) String() string {
+	var b strings.Builder
+	for _, c := range d.Changes {
+		label := string(c.Target)
+		if c.Label != "" {
+			label += " " + c.Label
+		}
+		switch c.Type {
+		case ItemChangeTypeAdded:
+			fmt.Fprintf(&b, "+ %s: %s\n", label, c.New)
+		case ItemChangeTypeRemoved:
+			fmt.Fprintf(&b, "- %s: %s\n", label, c.Old)
+		case ItemChangeTypeChanged:
+			fmt.Fprintf(&b, "~ %s: %s -> %s\n", label, c.Old, c.New)
+		}
+	}
+	return b.String()
+}
+
+// Represents a partial update of an item, applied against its latest version by Items().Patch.
+type ItemPatch struct {
+	// The new title of the item, if it changes
+	Title *string
+	// The new notes of the item, if they change
+	Notes *string
+	// Sections to add, or to replace if a section with the same ID exists
+	SetSections []ItemSection
+	// IDs of the sections to remove
+	RemoveSections []string
+	// Fields to add, or to replace if a field with the same ID exists
+	SetFields []ItemField
+	// IDs of the fields to remove
+	RemoveFields []string
+	// Tags to add
+	AddTags []string
+	// Tags to remove
+	RemoveTags []string
+	// Websites to add, or to replace if a website with the same URL exists
+	SetWebsites []Website
+	// URLs of the websites to remove
+	RemoveWebsites []string
+}
+
+// Apply applies the patch to the given item in place.
+func (p ItemPatch) Apply(item *Item) {
+	if p.Title != nil {
+		item.Title = *p.Title
+	}
+	if p.Notes != nil {
+		item.Notes = *p.Notes
+	}
+
+	item.Sections = slices.DeleteFunc(item.Sections, func(s ItemSection) bool { return slices.Contains(p.RemoveSections, s.ID) })
+	for _, section := range p.SetSections {
+		item.Sections = upsert(item.Sections, section, func(s ItemSection) bool { return s.ID == section.ID })
+	}
+
+	item.Fields = slices.DeleteFunc(item.Fields, func(f ItemField) bool { return slices.Contains(p.RemoveFields, f.ID) })
+	for _, field := range p.SetFields {
+		item.Fields = upsert(item.Fields, field, func(f ItemField) bool { return f.ID == field.ID })
+	}
+
+	item.Tags = slices.DeleteFunc(item.Tags, func(t string) bool { return slices.Contains(p.RemoveTags, t) })
+	for _, tag := range p.AddTags {
+		if !slices.Contains(item.Tags, tag) {
+			item.Tags = append(item.Tags, tag)
+		}
+	}
+
+	item.Websites = slices.DeleteFunc(item.Websites, func(w Website) bool { return slices.Contains(p.RemoveWebsites, w.URL) })
+	for _, website := range p.SetWebsites {
+		item.Websites = upsert(item.Websites, website, func(w Website) bool { return w.URL == website.URL })
+	}
+}
+
+// Patch applies a partial update to the latest version of an item, retrying when the item was changed concurrently.
+func (i ItemsSource) Patch(ctx context.Context, vaultID string, itemID string, patch ItemPatch) (Item, error) {
+	return i.Update(ctx, vaultID, itemID, func(item *Item) error {
+		patch.Apply(item)
+		return nil
+	})
+}
+
+// DiffItems describes the changes between item a and item b: fields, sections, tags, websites, notes, files and the document.
+// The values of secret fields, such as concealed fields, SSH keys and one-time passwords, are masked; use ItemChange.Reveal to access them.
+func DiffItems(a, b Item) ItemDiff {
+	var d ItemDiff
+
+	if a.Title != b.Title {
+		d.add(ItemChange{Type: ItemChangeTypeChanged, Target: ItemChangeTargetTitle, oldValue: a.Title, newValue: b.Title})
+		d.patch.Title = &b.Title
+	}
+	if a.Notes != b.Notes {
+		d.add(ItemChange{Type: ItemChangeTypeChanged, Target: ItemChangeTargetNotes, oldValue: a.Notes, newValue: b.Notes})
+		d.patch.Notes = &b.Notes
+	}
+
+	oldSections := indexBy(a.Sections, func(s ItemSection) string { return s.ID })
+	newSections := indexBy(b.Sections, func(s ItemSection) string { return s.ID })
+	for _, s := range b.Sections {
+		old, ok := oldSections[s.ID]
+		switch {
+		case !ok:
+			d.add(ItemChange{Type: ItemChangeTypeAdded, Target: ItemChangeTargetSection, Key: s.ID, Label: s.Title, newValue: s.Title})
+		case old.Title != s.Title:
+			d.add(ItemChange{Type: ItemChangeTypeChanged, Target: ItemChangeTargetSection, Key: s.ID, Label: s.Title, oldValue: old.Title, newValue: s.Title})
+		default:
+			continue
+		}
+		d.patch.SetSections = append(d.patch.SetSections, s)
+	}
+	for _, s := range a.Sections {
+		if _, ok := newSections[s.ID]; !ok {
+			d.add(ItemChange{Type: ItemChangeTypeRemoved, Target: ItemChangeTargetSection, Key: s.ID, Label: s.Title, oldValue: s.Title})
+			d.patch.RemoveSections = append(d.patch.RemoveSections, s.ID)
+		}
+	}
+
+	oldFields := indexBy(a.Fields, func(f ItemField) string { return f.ID })
+	newFields := indexBy(b.Fields, func(f ItemField) string { return f.ID })
+	for _, f := range b.Fields {
+		old, ok := oldFields[f.ID]
+		change := ItemChange{
+			Target:    ItemChangeTargetField,
+			Key:       f.ID,
+			Label:     fieldLabel(b, f),
+			Concealed: isSecretField(f) || (ok && isSecretField(old)),
+			newValue:  fieldDisplayValue(f),
+		}
+		switch {
+		case !ok:
+			change.Type = ItemChangeTypeAdded
+		case !fieldsEqual(old, f):
+			change.Type = ItemChangeTypeChanged
+			change.oldValue = fieldDisplayValue(old)
+		default:
+			continue
+		}
+		d.add(change)
+		d.patch.SetFields = append(d.patch.SetFields, f)
+	}
+	for _, f := range a.Fields {
+		if _, ok := newFields[f.ID]; !ok {
+			d.add(ItemChange{
+				Type:      ItemChangeTypeRemoved,
+				Target:    ItemChangeTargetField,
+				Key:       f.ID,
+				Label:     fieldLabel(a, f),
+				Concealed: isSecretField(f),
+				oldValue:  fieldDisplayValue(f),
+			})
+			d.patch.RemoveFields = append(d.patch.RemoveFields, f.ID)
+		}
+	}
+
+	for _, tag := range b.Tags {
+		if !slices.Contains(a.Tags, tag) {
+			d.add(ItemChange{Type: ItemChangeTypeAdded, Target: ItemChangeTargetTag, Key: tag, newValue: tag})
+			d.patch.AddTags = append(d.patch.AddTags, tag)
+		}
+	}
+	for _, tag := range a.Tags {
+		if !slices.Contains(b.Tags, tag) {
+			d.add(ItemChange{Type: ItemChangeTypeRemoved, Target: ItemChangeTargetTag, Key: tag, oldValue: tag})
+			d.patch.RemoveTags = append(d.patch.RemoveTags, tag)
+		}
+	}
+
+	oldWebsites := indexBy(a.Websites, func(w Website) string { return w.URL })
+	newWebsites := indexBy(b.Websites, func(w Website) string { return w.URL })
+	for _, w := range b.Websites {
+		old, ok := oldWebsites[w.URL]
+		switch {
+		case !ok:
+			d.add(ItemChange{Type: ItemChangeTypeAdded, Target: ItemChangeTargetWebsite, Key: w.URL, Label: w.Label, newValue: websiteDisplayValue(w)})
+		case old != w:
+			d.add(ItemChange{Type: ItemChangeTypeChanged, Target: ItemChangeTargetWebsite, Key: w.URL, Label: w.Label, oldValue: websiteDisplayValue(old), newValue: websiteDisplayValue(w)})
+		default:
+			continue
+		}
+		d.patch.SetWebsites = append(d.patch.SetWebsites, w)
+	}
+	for _, w := range a.Websites {
+		if _, ok := newWebsites[w.URL]; !ok {
+			d.add(ItemChange{Type: ItemChangeTypeRemoved, Target: ItemChangeTargetWebsite, Key: w.URL, Label: w.Label, oldValue: websiteDisplayValue(w)})
+			d.patch.RemoveWebsites = append(d.patch.RemoveWebsites, w.URL)
+		}
+	}
+
+	oldFiles := indexBy(a.Files, func(f ItemFile) string { return f.Attributes.ID })
+	newFiles := indexBy(b.Files, func(f ItemFile) string { return f.Attributes.ID })
+	for _, f := range b.Files {
+		if _, ok := oldFiles[f.Attributes.ID]; !ok {
+			d.add(ItemChange{Type: ItemChangeTypeAdded, Target: ItemChangeTargetFile, Key: f.Attributes.ID, Label: f.Attributes.Name, newValue: fileDisplayValue(f.Attributes)})
+		}
+	}
+	for _, f := range a.Files {
+		if _, ok := newFiles[f.Attributes.ID]; !ok {
+			d.add(ItemChange{Type: ItemChangeTypeRemoved, Target: ItemChangeTargetFile, Key: f.Attributes.ID, Label: f.Attributes.Name, oldValue: fileDisplayValue(f.Attributes)})
+		}
+	}
+
+	switch {
+	case a.Document == nil && b.Document != nil:
+		d.add(ItemChange{Type: ItemChangeTypeAdded, Target: ItemChangeTargetDocument, newValue: fileDisplayValue(*b.Document)})
+	case a.Document != nil && b.Document == nil:
+		d.add(ItemChange{Type: ItemChangeTypeRemoved, Target: ItemChangeTargetDocument, oldValue: fileDisplayValue(*a.Document)})
+	case a.Document != nil && b.Document != nil && *a.Document != *b.Document:
+		d.add(ItemChange{Type: ItemChangeTypeChanged, Target: ItemChangeTargetDocument, oldValue: fileDisplayValue(*a.Document), newValue: fileDisplayValue(*b.Document)})
+	}
+
+	return d
+}
+
+// isSecretField reports whether the value of a field must be masked when displaying changes.
+func isSecretField(f ItemField) bool {
+	switch f.FieldType {
+	case ItemFieldTypeConcealed, ItemFieldTypeSSHKey, ItemFieldTypeTOTP, ItemFieldTypeCreditCardNumber:
+		return true
+	}
+	return false
+}
+
+// add records a change, filling in its displayed values.
+func (d *ItemDiff) add(c ItemChange) {
+	c.Old, c.New = c.oldValue, c.newValue
+	if c.Concealed {
+		if c.Old != "" {
+			c.Old = maskedValue
+		}
+		if c.New != "" {
+			c.New = maskedValue
+		}
+	}
+	d.Changes = append(d.Changes, c)
+}
+
+func fieldsEqual(a, b ItemField) bool {
+	if a.Title != b.Title || a.FieldType != b.FieldType || a.Value != b.Value {
+		return false
+	}
+	if (a.SectionID == nil) != (b.SectionID == nil) || (a.SectionID != nil && *a.SectionID != *b.SectionID) {
+		return false
+	}
+	// Only address details are user provided, the others are computed from the value.
+	if a.FieldType == ItemFieldTypeAddress {
+		return reflect.DeepEqual(a.Details, b.Details)
+	}
+	return true
+}
+
+func fieldLabel(item Item, field ItemField) string {
+	if field.SectionID != nil {
+		for _, s := range item.Sections {
+			if s.ID == *field.SectionID && s.Title != "" {
+				return s.Title + "/" + field.Title
+			}
+		}
+	}
+	return field.Title
+}
+
+func fieldDisplayValue(field ItemField) string {
+	if field.FieldType == ItemFieldTypeAddress && field.Details != nil && field.Details.Type == ItemFieldDetailsTypeVariantAddress {
+		if a := field.Details.Address(); a != nil {
+			return strings.Join([]string{a.Street, a.City, a.State, a.Zip, a.Country}, ", ")
+		}
+	}
+	return field.Value
+}
+
+func websiteDisplayValue(w Website) string {
+	return fmt.Sprintf("%s (%s)", w.URL, w.AutofillBehavior)
+}
+
+func fileDisplayValue(attr FileAttributes) string {
+	return fmt.Sprintf("%s (%d bytes)", attr.Name, attr.Size)
+}
+
+func indexBy[T any](elems []T, key func(T) string) map[string]T {
+	index := make(map[string]T, len(elems))
+	for _, e := range elems {
+		index[key(e)] = e
+	}
+	return index
+}
+
+func upsert[T any](elems []T, elem T, matches func(T) bool) []T {
+	if i := slices.IndexFunc(elems, matches); i >= 0 {
+		elems[i] = elem
+		return elems
+	}
+	return append(elems, elem)
+}
@@ -18,6 +18,15 @@ type ItemsSharesAPI interface {
 
 	// Create a new item sharing link.
 	Create(ctx context.Context, item Item, policy ItemShareAccountPolicy, params ItemShareParams) (string, error)
+
+	// List the sharing links of an item, including expired and revoked ones.
+	List(ctx context.Context, vaultID string, itemID string) ([]ItemShare, error)
+
+	// Get a sharing link of an item by its ID.
+	Get(ctx context.Context, vaultID string, itemID string, shareID string) (ItemShare, error)
+
+	// Revoke a sharing link of an item before it expires.
+	Revoke(ctx context.Context, vaultID string, itemID string, shareID string) error
 }
 
 type ItemsSharesSource struct {
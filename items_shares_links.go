@@ -0,0 +1,94 @@
+package onepassword
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// The state of an item share link
+type ItemShareState string
+
+const (
+	// The share link can be used
+	ItemShareStateActive ItemShareState = "active"
+	// The share link reached its expiry date or maximum number of views
+	ItemShareStateExpired ItemShareState = "expired"
+	// The share link was revoked before it expired
+	ItemShareStateRevoked ItemShareState = "revoked"
+)
+
+// An item share link created with ItemsSharesAPI.Create
+type ItemShare struct {
+	// The share's ID
+	ID string `json:"id"`
+	// The ID of the shared item
+	ItemID string `json:"itemId"`
+	// The ID of the vault of the shared item
+	VaultID string `json:"vaultId"`
+	// The share link
+	URL string `json:"url"`
+	// Whether the share is public or restricted to its recipients
+	Type AllowedType `json:"type"`
+	// Emails or domains of the share recipients, empty for public shares
+	Recipients []ValidRecipient `json:"recipients,omitempty"`
+	// Whether the item can only be viewed once per recipient
+	OneTimeOnly bool `json:"oneTimeOnly"`
+	// The number of times the shared item was viewed
+	ViewCount uint32 `json:"viewCount"`
+	// The maximum number of times the item can be viewed. A null value means unlimited views
+	MaxViews *uint32 `json:"maxViews,omitempty"`
+	// The state of the share link
+	State ItemShareState `json:"state"`
+	// The time the share was created at
+	CreatedAt time.Time `json:"createdAt"`
+	// The time the share expires at
+	ExpiresAt time.Time `json:"expiresAt"`
+	// The time the share was revoked at, if it was
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+}
+
+// List the sharing links of an item, including expired and revoked ones.
+func (i ItemsSharesSource) List(ctx context.Context, vaultID string, itemID string) ([]ItemShare, error) {
+	resultString, err := clientInvoke(ctx, i.InnerClient, "ItemsSharesList", map[string]interface{}{
+		"vault_id": vaultID,
+		"item_id":  itemID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var result []ItemShare
+	err = json.Unmarshal([]byte(*resultString), &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Get a sharing link of an item by its ID.
+func (i ItemsSharesSource) Get(ctx context.Context, vaultID string, itemID string, shareID string) (ItemShare, error) {
+	resultString, err := clientInvoke(ctx, i.InnerClient, "ItemsSharesGet", map[string]interface{}{
+		"vault_id": vaultID,
+		"item_id":  itemID,
+		"share_id": shareID,
+	})
+	if err != nil {
+		return ItemShare{}, err
+	}
+	var result ItemShare
+	err = json.Unmarshal([]byte(*resultString), &result)
+	if err != nil {
+		return ItemShare{}, err
+	}
+	return result, nil
+}
+
+// Revoke a sharing link of an item before it expires.
+func (i ItemsSharesSource) Revoke(ctx context.Context, vaultID string, itemID string, shareID string) error {
+	_, err := clientInvoke(ctx, i.InnerClient, "ItemsSharesRevoke", map[string]interface{}{
+		"vault_id": vaultID,
+		"item_id":  itemID,
+		"share_id": shareID,
+	})
+	return err
+}
@@ -0,0 +1,112 @@
+package onepassword
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ItemSharePolicyViolation is a way in which share parameters don't respect the account's item sharing policy.
+type ItemSharePolicyViolation struct {
+	// The parameter or part of the item that violates the policy: "ExpireAfter", "Recipients" or "Files"
+	Field string
+	// A description of the violation
+	Message string
+}
+
+func (v ItemSharePolicyViolation) String() string {
+	return v.Field + ": " + v.Message
+}
+
+// itemShareDurationOrder ranks share durations from shortest to longest.
+var itemShareDurationOrder = []ItemShareDuration{
+	ItemShareDurationOneHour,
+	ItemShareDurationOneDay,
+	ItemShareDurationSevenDays,
+	ItemShareDurationFourteenDays,
+	ItemShareDurationThirtyDays,
+}
+
+// Validate checks share parameters against the policy locally, before calling ItemsSharesAPI.Create, and returns all
+// violations found, or none if the share is allowed. When the item has files or a document, the stricter file sharing
+// policy applies to them as well. File sizes are compared before encryption, so files just under the maximum size
+// may still be rejected by the server.
+func (p ItemShareAccountPolicy) Validate(params ItemShareParams, item Item) []ItemSharePolicyViolation {
+	var violations []ItemSharePolicyViolation
+	add := func(field string, format string, args ...any) {
+		violations = append(violations, ItemSharePolicyViolation{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	expiry := p.DefaultExpiry
+	if params.ExpireAfter != nil {
+		expiry = *params.ExpireAfter
+		if !slices.Contains(itemShareDurationOrder, expiry) {
+			add("ExpireAfter", "unknown duration %q", expiry)
+		} else if longerThan(expiry, p.MaxExpiry) {
+			add("ExpireAfter", "%s exceeds the maximum expiry of %s", expiry, p.MaxExpiry)
+		}
+	}
+
+	shareType := AllowedTypePublic
+	if len(params.Recipients) > 0 {
+		shareType = AllowedTypeAuthenticated
+	}
+	if !slices.Contains(p.AllowedTypes, shareType) {
+		add("Recipients", "%s shares are not allowed", shareType)
+	}
+	for _, recipient := range params.Recipients {
+		if recipientType := AllowedRecipientType(recipient.Type); !slices.Contains(p.AllowedRecipientTypes, recipientType) {
+			add("Recipients", "recipient %s: %s recipients are not allowed", recipientName(recipient), recipientType)
+		}
+	}
+
+	files := slices.Clone(item.Files)
+	if item.Document != nil {
+		files = append(files, ItemFile{Attributes: *item.Document})
+	}
+	if len(files) == 0 {
+		return violations
+	}
+	if !p.Files.Allowed {
+		add("Files", "items with files can't be shared")
+		return violations
+	}
+	if params.ExpireAfter == nil && p.Files.DefaultExpiry != nil {
+		// Shares of items with files default to the file sharing policy's expiry.
+		expiry = *p.Files.DefaultExpiry
+	}
+	for _, file := range files {
+		if p.Files.MaxSize > 0 && file.Attributes.Size > p.Files.MaxSize {
+			add("Files", "file %q is %d bytes, larger than the maximum of %d bytes", file.Attributes.Name, file.Attributes.Size, p.Files.MaxSize)
+		}
+	}
+	if p.Files.MaxExpiry != nil && longerThan(expiry, *p.Files.MaxExpiry) {
+		add("Files", "items with files can be shared for at most %s, not %s", *p.Files.MaxExpiry, expiry)
+	}
+	if len(p.Files.AllowedTypes) > 0 && !slices.Contains(p.Files.AllowedTypes, shareType) {
+		add("Files", "items with files can't be shared in %s shares", shareType)
+	}
+	if len(p.Files.AllowedRecipientTypes) > 0 {
+		for _, recipient := range params.Recipients {
+			if recipientType := AllowedRecipientType(recipient.Type); !slices.Contains(p.Files.AllowedRecipientTypes, recipientType) {
+				add("Files", "items with files can't be shared with %s recipients like %s", recipientType, recipientName(recipient))
+			}
+		}
+	}
+	return violations
+}
+
+// longerThan reports whether duration a is longer than b. Unknown durations are never longer.
+func longerThan(a, b ItemShareDuration) bool {
+	i, j := slices.Index(itemShareDurationOrder, a), slices.Index(itemShareDurationOrder, b)
+	return i >= 0 && j >= 0 && i > j
+}
+
+func recipientName(r ValidRecipient) string {
+	if e := r.Email(); e != nil {
+		return e.Email
+	}
+	if d := r.Domain(); d != nil {
+		return d.Domain
+	}
+	return string(r.Type)
+}
@@ -0,0 +1,47 @@
+package onepassword
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItemShareAccountPolicyValidate(t *testing.T) {
+	fileExpiry := ItemShareDurationOneDay
+	policy := ItemShareAccountPolicy{
+		MaxExpiry:             ItemShareDurationSevenDays,
+		DefaultExpiry:         ItemShareDurationOneDay,
+		AllowedTypes:          []AllowedType{AllowedTypeAuthenticated},
+		AllowedRecipientTypes: []AllowedRecipientType{AllowedRecipientTypeEmail},
+		Files:                 ItemShareFiles{Allowed: true, MaxSize: 1024, MaxExpiry: &fileExpiry},
+	}
+	email := NewValidRecipientTypeVariantEmail(&ValidRecipientEmailInner{Email: "jane@example.com"})
+	domain := NewValidRecipientTypeVariantDomain(&ValidRecipientDomainInner{Domain: "example.com"})
+
+	assert.Empty(t, policy.Validate(ItemShareParams{Recipients: []ValidRecipient{email}}, Item{}))
+
+	expiry := ItemShareDurationFourteenDays
+	violations := policy.Validate(ItemShareParams{
+		Recipients:  []ValidRecipient{email, domain},
+		ExpireAfter: &expiry,
+	}, Item{Files: []ItemFile{{Attributes: FileAttributes{Name: "dump.sql", Size: 4096}}}})
+
+	var fields []string
+	for _, v := range violations {
+		fields = append(fields, v.Field)
+	}
+	assert.Equal(t, []string{"ExpireAfter", "Recipients", "Files", "Files"}, fields)
+
+	// Without an expiry, shares of items with files use the default expiry of the file sharing policy.
+	withFiles := Item{Files: []ItemFile{{Attributes: FileAttributes{Name: "notes.txt", Size: 10}}}}
+	policy.DefaultExpiry = ItemShareDurationSevenDays
+	assert.Equal(t, []ItemSharePolicyViolation{
+		{Field: "Files", Message: "items with files can be shared for at most OneDay, not SevenDays"},
+	}, policy.Validate(ItemShareParams{Recipients: []ValidRecipient{email}}, withFiles))
+	fileDefaultExpiry := ItemShareDurationOneHour
+	policy.Files.DefaultExpiry = &fileDefaultExpiry
+	assert.Empty(t, policy.Validate(ItemShareParams{Recipients: []ValidRecipient{email}}, withFiles))
+
+	violations = policy.Validate(ItemShareParams{}, Item{})
+	assert.Equal(t, []ItemSharePolicyViolation{{Field: "Recipients", Message: "Public shares are not allowed"}}, violations)
+}
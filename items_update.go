@@ -0,0 +1,99 @@
+package onepassword
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// defaultItemUpdateAttempts is the number of times Update applies its mutation before giving up on a contended item.
+	defaultItemUpdateAttempts = 5
+	// defaultItemUpdateRetryDelay is the delay before the first retry of Update, doubled for every following retry.
+	defaultItemUpdateRetryDelay = 100 * time.Millisecond
+)
+
+// IncorrectItemVersionError is returned when an item is saved from a version that is no longer its latest one,
+// because it was changed concurrently.
+type IncorrectItemVersionError struct {
+	message string
+}
+
+func (e *IncorrectItemVersionError) Error() string {
+	return e.message
+}
+
+// unmarshalInvokeError maps the errors of invocations, including the item version conflicts that unmarshalError
+// doesn't know about. The core names these after the itemStatusIncorrectItemVersion variant of ItemUpdateFailureReason.
+func unmarshalInvokeError(err string) error {
+	v := struct {
+		Name    string `json:"name"`
+		Message string `json:"message"`
+	}{}
+	if e := json.Unmarshal([]byte(err), &v); e == nil && v.Name == string(ItemUpdateFailureReasonTypeVariantItemStatusIncorrectItemVersion) {
+		return &IncorrectItemVersionError{
+			message: v.Message,
+		}
+	}
+	return unmarshalError(err)
+}
+
+// ItemUpdateOptions configures how Update retries when the item was changed concurrently.
+type ItemUpdateOptions struct {
+	// The number of times the mutation is applied before giving up. Defaults to 5.
+	MaxAttempts int
+	// The delay before the first retry, doubled for every following retry. Defaults to 100ms.
+	RetryDelay time.Duration
+}
+
+// Update an existing item by applying a mutation to its latest version, retrying when the item was changed concurrently.
+// The mutation may be called more than once, each time with a freshly fetched copy of the item, so it must not have side effects.
+// Returning an error from the mutation aborts the update and returns that error.
+func (i ItemsSource) Update(ctx context.Context, vaultID string, itemID string, mutate func(*Item) error, opts ...ItemUpdateOptions) (Item, error) {
+	maxAttempts, delay := defaultItemUpdateAttempts, defaultItemUpdateRetryDelay
+	if len(opts) > 0 {
+		if opts[0].MaxAttempts > 0 {
+			maxAttempts = opts[0].MaxAttempts
+		}
+		if opts[0].RetryDelay > 0 {
+			delay = opts[0].RetryDelay
+		}
+	}
+
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return Item{}, ctx.Err()
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return Item{}, ctxErr
+		}
+
+		var item Item
+		item, err = i.Get(ctx, vaultID, itemID)
+		if err != nil {
+			return Item{}, err
+		}
+		if err = mutate(&item); err != nil {
+			return Item{}, err
+		}
+
+		var updated Item
+		updated, err = i.Put(ctx, item)
+		if err == nil {
+			return updated, nil
+		}
+		var conflict *IncorrectItemVersionError
+		if !errors.As(err, &conflict) {
+			return Item{}, err
+		}
+	}
+	return Item{}, fmt.Errorf("item %s was modified concurrently, gave up after %d attempts: %w", itemID, maxAttempts, err)
+}
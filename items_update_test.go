@@ -0,0 +1,97 @@
+package onepassword
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/1password/onepassword-sdk-go/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeUpdateCore serves a single item. The first conflicts updates of the item fail with the error the core returns
+// when the item was changed concurrently, and bump its version as that change would.
+type fakeUpdateCore struct {
+	item      Item
+	conflicts int
+	puts      int
+}
+
+func (c *fakeUpdateCore) InitClient(ctx context.Context, config []byte) ([]byte, error) {
+	return []byte("1"), nil
+}
+
+func (c *fakeUpdateCore) Invoke(ctx context.Context, invokeConfig []byte) ([]byte, error) {
+	name, params, err := parseInvocation(invokeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	switch name {
+	case "ItemsGet":
+		return json.Marshal(c.item)
+	case "ItemsPut":
+		c.puts++
+		var item Item
+		if err := json.Unmarshal(params["item"], &item); err != nil {
+			return nil, err
+		}
+		if c.conflicts > 0 {
+			c.conflicts--
+			c.item.Version++
+			return nil, errors.New(`{"name":"itemStatusIncorrectItemVersion","message":"the item was changed"}`)
+		}
+		if item.Version != c.item.Version {
+			return nil, errors.New(`{"name":"itemStatusIncorrectItemVersion","message":"stale version"}`)
+		}
+		item.Version++
+		c.item = item
+		return json.Marshal(c.item)
+	}
+	return nil, errors.New("unexpected invocation " + name)
+}
+
+func (c *fakeUpdateCore) ReleaseClient(clientID []byte) {}
+
+func TestUpdateRetriesConflicts(t *testing.T) {
+	ctx := context.Background()
+	core := &fakeUpdateCore{item: Item{ID: "item1", VaultID: "vault1", Title: "Old"}, conflicts: 2}
+	items := NewItemsSource(&internal.InnerClient{Core: internal.CoreWrapper{InnerCore: core}})
+	opts := ItemUpdateOptions{MaxAttempts: 3, RetryDelay: time.Millisecond}
+
+	var seen []uint32
+	item, err := items.Update(ctx, "vault1", "item1", func(item *Item) error {
+		seen = append(seen, item.Version)
+		item.Title = "New"
+		return nil
+	}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, "New", item.Title)
+	assert.Equal(t, []uint32{0, 1, 2}, seen, "each attempt should start from the latest version")
+	assert.Equal(t, 3, core.puts)
+
+	core.conflicts, core.puts = 5, 0
+	_, err = items.Update(ctx, "vault1", "item1", func(item *Item) error { return nil }, opts)
+	var conflict *IncorrectItemVersionError
+	require.ErrorAs(t, err, &conflict)
+	assert.ErrorContains(t, err, "gave up after 3 attempts")
+	assert.Equal(t, 3, core.puts)
+
+	core.puts = 0
+	_, err = items.Update(ctx, "vault1", "item1", func(item *Item) error { return errors.New("invalid item") }, opts)
+	assert.EqualError(t, err, "invalid item")
+	assert.Equal(t, 0, core.puts, "a failed mutation must not be saved")
+}
+
+func TestUnmarshalInvokeError(t *testing.T) {
+	var conflict *IncorrectItemVersionError
+	require.ErrorAs(t, unmarshalInvokeError(`{"name":"itemStatusIncorrectItemVersion","message":"the item was changed"}`), &conflict)
+	assert.EqualError(t, conflict, "the item was changed")
+
+	assert.False(t, errors.As(unmarshalInvokeError(`{"name":"IncorrectItemVersion","message":"other"}`), &conflict))
+	var rateLimit *RateLimitExceededError
+	assert.ErrorAs(t, unmarshalInvokeError(`{"name":"RateLimitExceeded","message":"slow down"}`), &rateLimit)
+}
@@ -0,0 +1,221 @@
+// Package materialise writes secrets and file attachments into a directory, typically a tmpfs mount shared with
+// another process, such as the TLS certificates and keys read by nginx or Postgres running next to a sidecar.
+//
+// Every file is written atomically: its content goes into a temporary file in the same directory, which is then
+// renamed over the target, so readers never see a partially written file. Write fetches everything once; Run keeps
+// the files up to date until its context is cancelled and then removes them.
+package materialise
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/1password/onepassword-sdk-go"
+)
+
+// DefaultFileMode is the mode of written files when Entry.Mode isn't set: readable by the owner only.
+const DefaultFileMode fs.FileMode = 0o400
+
+// Entry is a file to write.
+type Entry struct {
+	// The path of the file, relative to the target directory, e.g. "tls/server.key".
+	Path string
+	// The secret reference to write, e.g. "op://prod/db/password". Exactly one of Reference and File must be set.
+	Reference string
+	// The file attachment or document to write.
+	File *FileSource
+	// The permissions of the file. Defaults to DefaultFileMode.
+	Mode fs.FileMode
+}
+
+// FileSource identifies a file attached to an item, or the document of a Document item.
+type FileSource struct {
+	VaultID string
+	ItemID  string
+	// The name of the attached file. If empty, the item's document is used.
+	Name string
+}
+
+// Write fetches the content of all entries and writes them into dir, creating it and any parent directories of the
+// entries with mode 0700 if needed. Nothing is written unless every entry could be fetched.
+// It returns the paths of the files whose content changed.
+func Write(ctx context.Context, client *onepassword.Client, dir string, entries []Entry) ([]string, error) {
+	contents, err := fetch(ctx, client, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	var errs []error
+	for i, entry := range entries {
+		target := filepath.Join(dir, filepath.FromSlash(entry.Path))
+		mode := entry.Mode
+		if mode == 0 {
+			mode = DefaultFileMode
+		}
+		written, err := writeFile(target, contents[i], mode)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error writing %s: %w", entry.Path, err))
+			continue
+		}
+		if written {
+			changed = append(changed, entry.Path)
+		}
+	}
+	return changed, errors.Join(errs...)
+}
+
+// RunOptions configure Run.
+type RunOptions struct {
+	// How often the entries are fetched again. If zero, they're only written once.
+	RefreshInterval time.Duration
+	// Called with the paths of the files whose content changed after each refresh, e.g. to signal a server to reload.
+	OnChange func(paths []string)
+	// Called when a refresh fails. The previously written files are left in place and the next refresh is attempted as usual.
+	OnError func(err error)
+	// Leave the written files in place when the context is cancelled or the first write fails, instead of removing them.
+	KeepOnExit bool
+}
+
+// Run writes the entries into dir and keeps them up to date until the context is cancelled. Then it removes the
+// written files, unless opts.KeepOnExit is set, and returns. Only the first write is required to succeed; if it
+// fails, the files whose content it wrote are removed the same way before returning, while files that already
+// had the right content are left in place.
+func Run(ctx context.Context, client *onepassword.Client, dir string, entries []Entry, opts RunOptions) error {
+	if changed, err := Write(ctx, client, dir, entries); err != nil {
+		if opts.KeepOnExit {
+			return err
+		}
+		written := slices.DeleteFunc(slices.Clone(entries), func(entry Entry) bool { return !slices.Contains(changed, entry.Path) })
+		return errors.Join(err, Remove(dir, written))
+	}
+
+	if opts.RefreshInterval > 0 {
+		ticker := time.NewTicker(opts.RefreshInterval)
+		defer ticker.Stop()
+	refresh:
+		for {
+			select {
+			case <-ctx.Done():
+				break refresh
+			case <-ticker.C:
+			}
+			changed, err := Write(ctx, client, dir, entries)
+			if err != nil && ctx.Err() == nil && opts.OnError != nil {
+				opts.OnError(err)
+			}
+			if len(changed) > 0 && opts.OnChange != nil {
+				opts.OnChange(changed)
+			}
+		}
+	} else {
+		<-ctx.Done()
+	}
+
+	if opts.KeepOnExit {
+		return nil
+	}
+	return Remove(dir, entries)
+}
+
+// Remove deletes the files of the entries from dir. Files that don't exist are ignored.
+func Remove(dir string, entries []Entry) error {
+	var errs []error
+	for _, entry := range entries {
+		if err := os.Remove(filepath.Join(dir, filepath.FromSlash(entry.Path))); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// fetch returns the content of each entry, resolving all references in a single call and reading each item once.
+func fetch(ctx context.Context, client *onepassword.Client, entries []Entry) ([][]byte, error) {
+	var references []string
+	for _, entry := range entries {
+		if !filepath.IsLocal(filepath.FromSlash(entry.Path)) {
+			return nil, fmt.Errorf("path %q must be relative and stay within the target directory", entry.Path)
+		}
+		if (entry.Reference == "") == (entry.File == nil) {
+			return nil, fmt.Errorf("entry %s must have either a reference or a file", entry.Path)
+		}
+		if entry.Reference != "" {
+			references = append(references, entry.Reference)
+		}
+	}
+
+	var resolved onepassword.ResolveAllResponse
+	if len(references) > 0 {
+		var err error
+		resolved, err = client.Secrets().ResolveAll(ctx, references)
+		if err != nil {
+			return nil, fmt.Errorf("error resolving secret references: %w", err)
+		}
+	}
+
+	items := map[[2]string]onepassword.Item{}
+	contents := make([][]byte, len(entries))
+	var errs []error
+	for i, entry := range entries {
+		if entry.Reference != "" {
+			response, ok := resolved.IndividualResponses[entry.Reference]
+			switch {
+			case !ok:
+				errs = append(errs, fmt.Errorf("error resolving %s: no response", entry.Reference))
+			case response.Error != nil:
+				errs = append(errs, fmt.Errorf("error resolving %s: %s", entry.Reference, response.Error.Type))
+			case response.Content != nil:
+				contents[i] = []byte(response.Content.Secret)
+			}
+			continue
+		}
+
+		key := [2]string{entry.File.VaultID, entry.File.ItemID}
+		item, ok := items[key]
+		if !ok {
+			var err error
+			item, err = client.Items().Get(ctx, entry.File.VaultID, entry.File.ItemID)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("error getting item %s: %w", entry.File.ItemID, err))
+				continue
+			}
+			items[key] = item
+		}
+		attr, err := findFile(item, entry.File.Name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		content, err := client.Items().Files().Read(ctx, entry.File.VaultID, entry.File.ItemID, attr)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error reading file %q of item %s: %w", attr.Name, item.ID, err))
+			continue
+		}
+		contents[i] = content
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return contents, nil
+}
+
+func findFile(item onepassword.Item, name string) (onepassword.FileAttributes, error) {
+	if name == "" {
+		if item.Document == nil {
+			return onepassword.FileAttributes{}, fmt.Errorf("item %s has no document", item.ID)
+		}
+		return *item.Document, nil
+	}
+	for _, file := range item.Files {
+		if file.Attributes.Name == name {
+			return file.Attributes, nil
+		}
+	}
+	return onepassword.FileAttributes{}, fmt.Errorf("item %s has no file named %q", item.ID, name)
+}
@@ -0,0 +1,110 @@
+package materialise
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/1password/onepassword-sdk-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSecrets struct {
+	onepassword.SecretsAPI
+	secrets map[string]string
+}
+
+func (f fakeSecrets) ResolveAll(ctx context.Context, references []string) (onepassword.ResolveAllResponse, error) {
+	response := onepassword.ResolveAllResponse{IndividualResponses: map[string]onepassword.Response[onepassword.ResolvedReference, onepassword.ResolveReferenceError]{}}
+	for _, reference := range references {
+		response.IndividualResponses[reference] = onepassword.Response[onepassword.ResolvedReference, onepassword.ResolveReferenceError]{
+			Content: &onepassword.ResolvedReference{Secret: f.secrets[reference]},
+		}
+	}
+	return response, nil
+}
+
+type fakeItems struct {
+	onepassword.ItemsAPI
+	item onepassword.Item
+}
+
+func (f fakeItems) Get(ctx context.Context, vaultID string, itemID string) (onepassword.Item, error) {
+	return f.item, nil
+}
+
+func (f fakeItems) Files() onepassword.ItemsFilesAPI {
+	return fakeFiles{}
+}
+
+type fakeFiles struct {
+	onepassword.ItemsFilesAPI
+}
+
+func (fakeFiles) Read(ctx context.Context, vaultID string, itemID string, attr onepassword.FileAttributes) ([]byte, error) {
+	return []byte("content of " + attr.Name), nil
+}
+
+func TestWriteAndRun(t *testing.T) {
+	dir := t.TempDir()
+	client := &onepassword.Client{
+		SecretsAPI: fakeSecrets{secrets: map[string]string{"op://prod/db/password": "hunter2"}},
+		ItemsAPI: fakeItems{item: onepassword.Item{
+			ID:    "tls",
+			Files: []onepassword.ItemFile{{Attributes: onepassword.FileAttributes{ID: "f1", Name: "server.key"}}},
+		}},
+	}
+	entries := []Entry{
+		{Path: "db/password", Reference: "op://prod/db/password"},
+		{Path: "tls/server.key", File: &FileSource{VaultID: "prod", ItemID: "tls", Name: "server.key"}, Mode: 0o440},
+	}
+
+	changed, err := Write(context.Background(), client, dir, entries)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"db/password", "tls/server.key"}, changed)
+
+	password, err := os.ReadFile(filepath.Join(dir, "db", "password"))
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", string(password))
+	info, err := os.Stat(filepath.Join(dir, "tls", "server.key"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o440), info.Mode().Perm())
+
+	changed, err = Write(context.Background(), client, dir, entries)
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+
+	_, err = Write(context.Background(), client, dir, []Entry{{Path: "../escape", Reference: "op://prod/db/password"}})
+	assert.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NoError(t, Run(ctx, client, dir, entries, RunOptions{}))
+	assert.NoFileExists(t, filepath.Join(dir, "db", "password"))
+	assert.NoFileExists(t, filepath.Join(dir, "tls", "server.key"))
+}
+
+func TestRunRemovesFilesWhenFirstWriteFails(t *testing.T) {
+	dir := t.TempDir()
+	client := &onepassword.Client{SecretsAPI: fakeSecrets{secrets: map[string]string{"op://prod/db/password": "hunter2"}}}
+	// The second entry can't be written, since its parent is the file written for the first one.
+	entries := []Entry{
+		{Path: "password", Reference: "op://prod/db/password"},
+		{Path: "password/copy", Reference: "op://prod/db/password"},
+	}
+
+	require.Error(t, Run(context.Background(), client, dir, entries, RunOptions{}))
+	assert.NoFileExists(t, filepath.Join(dir, "password"))
+
+	// Files that were already there before the run are kept.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "existing"), []byte("hunter2"), DefaultFileMode))
+	existing := append([]Entry{{Path: "existing", Reference: "op://prod/db/password"}}, entries...)
+	require.Error(t, Run(context.Background(), client, dir, existing, RunOptions{}))
+	assert.FileExists(t, filepath.Join(dir, "existing"))
+	assert.NoFileExists(t, filepath.Join(dir, "password"))
+
+	require.Error(t, Run(context.Background(), client, dir, entries, RunOptions{KeepOnExit: true}))
+	assert.FileExists(t, filepath.Join(dir, "password"))
+}
@@ -0,0 +1,156 @@
+package onepassword
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Permission wraps a raw set of vault permissions, as found in VaultAccess, GroupAccess and GroupVaultAccess,
+// to parse, format and check it, e.g. Permission(access.Permissions).Has(ReadItems).
+// Convert it back with uint32(p) to grant or update permissions.
+type Permission uint32
+
+// Presets matching the roles that can be given to people and groups for a vault in the 1Password apps.
+// Like ReadItems and the other raw permissions, they can be combined with the bitwise or operator.
+const (
+	// View, copy and reveal items and their history
+	ViewPermissions uint32 = ReadItems | RevealItemPassword | UpdateItemHistory
+	// View, create, edit, archive and delete items, and import, export, send and print them
+	EditPermissions uint32 = ViewPermissions | CreateItems | UpdateItems | ArchiveItems | DeleteItems | ImportItems | ExportItems | SendItems | PrintItems
+	// Edit items and manage who has access to the vault
+	ManagePermissions uint32 = EditPermissions | ManageVault
+)
+
+// permissionNames lists every permission with its name, in the order String formats them: the item permissions
+// from viewing to deleting items, then those to move items in and out of the vault, then the vault permissions.
+var permissionNames = []struct {
+	permission uint32
+	name       string
+}{
+	{ReadItems, "read_items"},
+	{RevealItemPassword, "reveal_item_password"},
+	{UpdateItemHistory, "update_item_history"},
+	{CreateItems, "create_items"},
+	{UpdateItems, "update_items"},
+	{ArchiveItems, "archive_items"},
+	{DeleteItems, "delete_items"},
+	{ImportItems, "import_items"},
+	{ExportItems, "export_items"},
+	{SendItems, "send_items"},
+	{PrintItems, "print_items"},
+	{ManageVault, "manage_vault"},
+	{RecoverVault, "recover_vault"},
+}
+
+// permissionPresets maps the names accepted by ParsePermissions for the presets.
+var permissionPresets = map[string]uint32{
+	"view":   ViewPermissions,
+	"edit":   EditPermissions,
+	"manage": ManagePermissions,
+}
+
+// permissionDependencies lists the permissions each permission requires to be granted as well.
+var permissionDependencies = map[uint32]uint32{
+	RevealItemPassword: ReadItems,
+	UpdateItemHistory:  ReadItems | RevealItemPassword,
+	CreateItems:        ReadItems | RevealItemPassword | UpdateItemHistory,
+	UpdateItems:        ReadItems | RevealItemPassword | UpdateItemHistory,
+	ArchiveItems:       ReadItems | RevealItemPassword | UpdateItemHistory | UpdateItems,
+	DeleteItems:        ReadItems | RevealItemPassword | UpdateItemHistory | UpdateItems,
+	SendItems:          ReadItems | RevealItemPassword | UpdateItemHistory,
+	ImportItems:        ReadItems | RevealItemPassword | UpdateItemHistory | CreateItems,
+	ExportItems:        ReadItems | RevealItemPassword | UpdateItemHistory,
+	PrintItems:         ReadItems | RevealItemPassword | UpdateItemHistory,
+}
+
+// String formats the permissions as their names separated by "|", or "no_access" if there are none.
+// The names always come in the same order, regardless of their bit values: read_items, reveal_item_password,
+// update_item_history, create_items, update_items, archive_items, delete_items, import_items, export_items,
+// send_items, print_items, manage_vault and recover_vault, e.g. "read_items|reveal_item_password".
+// Unknown bits are formatted last, as a hexadecimal number.
+func (p Permission) String() string {
+	if uint32(p) == NoAccess {
+		return "no_access"
+	}
+	var names []string
+	remaining := p
+	for _, n := range permissionNames {
+		if p.Has(n.permission) {
+			names = append(names, n.name)
+			remaining = remaining.Remove(n.permission)
+		}
+	}
+	if remaining != 0 {
+		names = append(names, "0x"+strconv.FormatUint(uint64(remaining), 16))
+	}
+	return strings.Join(names, "|")
+}
+
+// ParsePermissions parses permissions formatted by Permission.String. Names can also be separated by commas,
+// and the presets can be given as "view", "edit" and "manage".
+func ParsePermissions(s string) (Permission, error) {
+	var p Permission
+	for _, name := range strings.FieldsFunc(s, func(r rune) bool { return r == '|' || r == ',' }) {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if preset, ok := permissionPresets[name]; ok {
+			p = p.Add(preset)
+			continue
+		}
+		if name == "no_access" {
+			continue
+		}
+		found := false
+		for _, n := range permissionNames {
+			if n.name == name {
+				p = p.Add(n.permission)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return Permission(NoAccess), fmt.Errorf("unknown permission %q", name)
+		}
+	}
+	return p, nil
+}
+
+// Has reports whether all of the given raw permissions are included.
+func (p Permission) Has(permissions uint32) bool {
+	return uint32(p)&permissions == permissions
+}
+
+// Add returns the permissions with the given raw permissions included.
+func (p Permission) Add(permissions uint32) Permission {
+	return p | Permission(permissions)
+}
+
+// Remove returns the permissions without the given raw permissions.
+func (p Permission) Remove(permissions uint32) Permission {
+	return p &^ Permission(permissions)
+}
+
+// WithDependencies returns the permissions together with all the permissions they require.
+func (p Permission) WithDependencies() Permission {
+	for permission, required := range permissionDependencies {
+		if p.Has(permission) {
+			p = p.Add(required)
+		}
+	}
+	return p
+}
+
+// Validate checks that every permission that requires others comes with them, e.g. that reveal_item_password
+// is accompanied by read_items, and returns an error listing each missing dependency.
+func (p Permission) Validate() error {
+	var errs []error
+	for _, n := range permissionNames {
+		required, ok := permissionDependencies[n.permission]
+		if !ok || !p.Has(n.permission) || p.Has(required) {
+			continue
+		}
+		errs = append(errs, fmt.Errorf("%s requires %s", n.name, Permission(required).Remove(uint32(p))))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,46 @@
+package onepassword
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermission(t *testing.T) {
+	assert.Equal(t, "read_items|reveal_item_password", Permission(RevealItemPassword|ReadItems).String())
+	assert.Equal(t, "no_access", Permission(NoAccess).String())
+	assert.Equal(t, "read_items|0x4", Permission(ReadItems|4).String())
+	assert.Equal(t, "read_items|reveal_item_password|update_item_history|create_items|update_items|archive_items|"+
+		"delete_items|import_items|export_items|send_items|print_items|manage_vault|recover_vault", Permission(ManagePermissions|RecoverVault).String())
+
+	p, err := ParsePermissions("reveal_item_password, read_items")
+	require.NoError(t, err)
+	assert.Equal(t, ReadItems|RevealItemPassword, uint32(p))
+
+	p, err = ParsePermissions(Permission(ManagePermissions).String())
+	require.NoError(t, err)
+	assert.Equal(t, ManagePermissions, uint32(p))
+
+	p, err = ParsePermissions("view|send_items")
+	require.NoError(t, err)
+	assert.True(t, p.Has(ViewPermissions))
+	assert.False(t, p.Remove(SendItems).Has(SendItems))
+	assert.True(t, p.Add(ManageVault).Has(ManageVault))
+
+	_, err = ParsePermissions("read_items|fly")
+	assert.Error(t, err)
+
+	assert.NoError(t, Permission(EditPermissions).Validate())
+	assert.EqualError(t, Permission(RevealItemPassword).Validate(), "reveal_item_password requires read_items")
+	assert.NoError(t, Permission(ArchiveItems).WithDependencies().Validate())
+
+	assert.NoError(t, Permission(ManagePermissions).Validate())
+}
+
+func TestPermissionPresets(t *testing.T) {
+	assert.Equal(t, ReadItems|RevealItemPassword|UpdateItemHistory, ViewPermissions)
+	assert.Equal(t, ReadItems|RevealItemPassword|UpdateItemHistory|CreateItems|UpdateItems|ArchiveItems|DeleteItems|
+		ImportItems|ExportItems|SendItems|PrintItems, EditPermissions)
+	assert.Equal(t, EditPermissions|ManageVault, ManagePermissions)
+}
@@ -0,0 +1,224 @@
+package onepassword
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/1password/onepassword-sdk-go/internal"
+)
+
+// The state of a user account
+type UserState string
+
+const (
+	// The user was invited and hasn't joined the account yet
+	UserStateInvited UserState = "invited"
+	// The user is an active member of the account
+	UserStateActive UserState = "active"
+	// The user was suspended and can't access the account
+	UserStateSuspended UserState = "suspended"
+	// The user is in an unknown state
+	UserStateUnsupported UserState = "unsupported"
+)
+
+// The type of a user account
+type UserType string
+
+const (
+	// A team member, who can be given access to any vault
+	UserTypeMember UserType = "member"
+	// A guest, who can only be given access to a single vault
+	UserTypeGuest UserType = "guest"
+)
+
+// Represents a user of a 1Password account.
+type User struct {
+	// The user's ID
+	ID string `json:"id"`
+	// The user's name
+	Name string `json:"name"`
+	// The user's email address
+	Email string `json:"email"`
+	// The user's type
+	UserType UserType `json:"userType"`
+	// The user's state
+	State UserState `json:"state"`
+	// The time the user was created at
+	CreatedAt time.Time `json:"createdAt"`
+	// The time the user was last updated at
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+type UserListParams struct {
+	// Only list users in this state
+	State *UserState `json:"state,omitempty"`
+}
+type UserInviteParams struct {
+	// The email address to send the invitation to
+	Email string `json:"email"`
+	// The user's name
+	Name string `json:"name"`
+	// The user's type. Defaults to a team member
+	UserType *UserType `json:"userType,omitempty"`
+}
+
+// Represents a user's membership of a group.
+type UserGroupMembership struct {
+	// The group's ID
+	GroupID string `json:"groupId"`
+	// The group's title
+	Title string `json:"title"`
+	// The user's role within the group
+	Role GroupRole `json:"role"`
+}
+
+// The Users API holds all the operations the SDK client can perform on the users of a 1Password account.
+type UsersAPI interface {
+	// List the users of the account, optionally filtered by state.
+	List(ctx context.Context, params ...UserListParams) ([]User, error)
+
+	// Get a user by their ID.
+	Get(ctx context.Context, userID string) (User, error)
+
+	// Get a user by their email address.
+	GetByEmail(ctx context.Context, email string) (User, error)
+
+	// Invite a new user to the account.
+	Invite(ctx context.Context, params UserInviteParams) (User, error)
+
+	// Suspend a user, revoking their access to the account until they're reactivated.
+	Suspend(ctx context.Context, userID string) error
+
+	// Reactivate a suspended user.
+	Reactivate(ctx context.Context, userID string) error
+
+	// List the groups a user is a member of.
+	ListGroups(ctx context.Context, userID string) ([]UserGroupMembership, error)
+
+	// List the vaults a user has access to, either directly or through their groups.
+	ListVaultAccess(ctx context.Context, userID string) ([]VaultAccess, error)
+}
+
+type UsersSource struct {
+	*internal.InnerClient
+}
+
+func NewUsersSource(inner *internal.InnerClient) UsersAPI {
+	return &UsersSource{InnerClient: inner}
+}
+
+// List the users of the account, optionally filtered by state.
+func (u UsersSource) List(ctx context.Context, params ...UserListParams) ([]User, error) {
+	var param *UserListParams
+	if len(params) > 0 {
+		param = &params[0]
+	}
+	resultString, err := clientInvoke(ctx, u.InnerClient, "UsersList", map[string]interface{}{
+		"params": param,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var result []User
+	err = json.Unmarshal([]byte(*resultString), &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Get a user by their ID.
+func (u UsersSource) Get(ctx context.Context, userID string) (User, error) {
+	resultString, err := clientInvoke(ctx, u.InnerClient, "UsersGet", map[string]interface{}{
+		"user_id": userID,
+	})
+	if err != nil {
+		return User{}, err
+	}
+	var result User
+	err = json.Unmarshal([]byte(*resultString), &result)
+	if err != nil {
+		return User{}, err
+	}
+	return result, nil
+}
+
+// Get a user by their email address.
+func (u UsersSource) GetByEmail(ctx context.Context, email string) (User, error) {
+	resultString, err := clientInvoke(ctx, u.InnerClient, "UsersGetByEmail", map[string]interface{}{
+		"email": email,
+	})
+	if err != nil {
+		return User{}, err
+	}
+	var result User
+	err = json.Unmarshal([]byte(*resultString), &result)
+	if err != nil {
+		return User{}, err
+	}
+	return result, nil
+}
+
+// Invite a new user to the account.
+func (u UsersSource) Invite(ctx context.Context, params UserInviteParams) (User, error) {
+	resultString, err := clientInvoke(ctx, u.InnerClient, "UsersInvite", map[string]interface{}{
+		"params": params,
+	})
+	if err != nil {
+		return User{}, err
+	}
+	var result User
+	err = json.Unmarshal([]byte(*resultString), &result)
+	if err != nil {
+		return User{}, err
+	}
+	return result, nil
+}
+
+// Suspend a user, revoking their access to the account until they're reactivated.
+func (u UsersSource) Suspend(ctx context.Context, userID string) error {
+	_, err := clientInvoke(ctx, u.InnerClient, "UsersSuspend", map[string]interface{}{
+		"user_id": userID,
+	})
+	return err
+}
+
+// Reactivate a suspended user.
+func (u UsersSource) Reactivate(ctx context.Context, userID string) error {
+	_, err := clientInvoke(ctx, u.InnerClient, "UsersReactivate", map[string]interface{}{
+		"user_id": userID,
+	})
+	return err
+}
+
+// List the groups a user is a member of.
+func (u UsersSource) ListGroups(ctx context.Context, userID string) ([]UserGroupMembership, error) {
+	resultString, err := clientInvoke(ctx, u.InnerClient, "UsersListGroups", map[string]interface{}{
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var result []UserGroupMembership
+	err = json.Unmarshal([]byte(*resultString), &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// List the vaults a user has access to, either directly or through their groups.
+func (u UsersSource) ListVaultAccess(ctx context.Context, userID string) ([]VaultAccess, error) {
+	resultString, err := clientInvoke(ctx, u.InnerClient, "UsersListVaultAccess", map[string]interface{}{
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var result []VaultAccess
+	err = json.Unmarshal([]byte(*resultString), &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
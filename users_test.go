@@ -0,0 +1,100 @@
+package onepassword
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fakeUser = `{
+	"id": "user1",
+	"name": "Jane",
+	"email": "jane@example.com",
+	"userType": "guest",
+	"state": "invited",
+	"createdAt": "2024-01-01T00:00:00Z",
+	"updatedAt": "2024-01-02T00:00:00Z"
+}`
+
+func TestUsers(t *testing.T) {
+	ctx := context.Background()
+	core := &fakeRecordingCore{results: map[string]string{
+		"UsersList":       "[" + fakeUser + "]",
+		"UsersGet":        fakeUser,
+		"UsersGetByEmail": fakeUser,
+		"UsersInvite":     fakeUser,
+	}}
+	users := NewUsersSource(core.inner())
+	expected := User{
+		ID:        "user1",
+		Name:      "Jane",
+		Email:     "jane@example.com",
+		UserType:  UserTypeGuest,
+		State:     UserStateInvited,
+		CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+
+	list, err := users.List(ctx)
+	require.NoError(t, err)
+	core.assertInvoked(t, "UsersList", `{"params": null}`)
+	assert.Equal(t, []User{expected}, list)
+
+	state := UserStateSuspended
+	_, err = users.List(ctx, UserListParams{State: &state})
+	require.NoError(t, err)
+	core.assertInvoked(t, "UsersList", `{"params": {"state": "suspended"}}`)
+
+	user, err := users.Get(ctx, "user1")
+	require.NoError(t, err)
+	core.assertInvoked(t, "UsersGet", `{"user_id": "user1"}`)
+	assert.Equal(t, expected, user)
+
+	_, err = users.GetByEmail(ctx, "jane@example.com")
+	require.NoError(t, err)
+	core.assertInvoked(t, "UsersGetByEmail", `{"email": "jane@example.com"}`)
+}
+
+func TestUsersInviteAndSuspend(t *testing.T) {
+	ctx := context.Background()
+	core := &fakeRecordingCore{results: map[string]string{"UsersInvite": fakeUser}}
+	users := NewUsersSource(core.inner())
+
+	_, err := users.Invite(ctx, UserInviteParams{Email: "jane@example.com", Name: "Jane"})
+	require.NoError(t, err)
+	core.assertInvoked(t, "UsersInvite", `{"params": {"email": "jane@example.com", "name": "Jane"}}`)
+
+	guest := UserTypeGuest
+	user, err := users.Invite(ctx, UserInviteParams{Email: "jane@example.com", Name: "Jane", UserType: &guest})
+	require.NoError(t, err)
+	core.assertInvoked(t, "UsersInvite", `{"params": {"email": "jane@example.com", "name": "Jane", "userType": "guest"}}`)
+	assert.Equal(t, UserStateInvited, user.State)
+
+	require.NoError(t, users.Suspend(ctx, "user1"))
+	core.assertInvoked(t, "UsersSuspend", `{"user_id": "user1"}`)
+
+	require.NoError(t, users.Reactivate(ctx, "user1"))
+	core.assertInvoked(t, "UsersReactivate", `{"user_id": "user1"}`)
+}
+
+func TestUserMemberships(t *testing.T) {
+	ctx := context.Background()
+	core := &fakeRecordingCore{results: map[string]string{
+		"UsersListGroups":      `[{"groupId": "group1", "title": "Developers", "role": "member"}]`,
+		"UsersListVaultAccess": `[{"vaultUuid": "vault1", "accessorType": "user", "accessorUuid": "user1", "permissions": 32}]`,
+	}}
+	users := NewUsersSource(core.inner())
+
+	groups, err := users.ListGroups(ctx, "user1")
+	require.NoError(t, err)
+	core.assertInvoked(t, "UsersListGroups", `{"user_id": "user1"}`)
+	assert.Equal(t, []UserGroupMembership{{GroupID: "group1", Title: "Developers", Role: GroupRoleMember}}, groups)
+
+	access, err := users.ListVaultAccess(ctx, "user1")
+	require.NoError(t, err)
+	core.assertInvoked(t, "UsersListVaultAccess", `{"user_id": "user1"}`)
+	assert.Equal(t, []VaultAccess{{VaultUuid: "vault1", AccessorType: VaultAccessorTypeUser, AccessorUuid: "user1", Permissions: ReadItems}}, access)
+}
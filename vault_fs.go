@@ -0,0 +1,263 @@
+package onepassword
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// VaultFS returns a read-only file system view over the active items of a vault, for use with code that consumes
+// fs.FS, such as http.FileServer or template.ParseFS.
+//
+// Each item is a directory named after its title. It holds a file per field, named after the field's title, and the
+// item's attached files and document under their file names. Fields of titled sections are placed in a subdirectory
+// named after the section. Names that would clash are suffixed with "~2", "~3" and so on.
+// For example, the password of a "db-prod" item is read from "db-prod/password".
+//
+// The item list, items and file contents are fetched on first use and cached for the lifetime of the file system,
+// all using the given context. Call VaultFS again to get a fresh view.
+func VaultFS(ctx context.Context, client *Client, vaultID string) fs.FS {
+	return &vaultFS{ctx: ctx, client: client, vaultID: vaultID}
+}
+
+type vaultFS struct {
+	ctx     context.Context
+	client  *Client
+	vaultID string
+
+	mu   sync.Mutex
+	root *vaultFSNode
+}
+
+// vaultFSNode is a directory or file of a vault file system. It serves as its own fs.FileInfo and fs.DirEntry.
+type vaultFSNode struct {
+	name    string
+	modTime time.Time
+	dir     bool
+
+	// For directories: the children, and the ID of the item to load them from, until it's loaded.
+	children   []*vaultFSNode
+	lazyItemID string
+
+	// For files: the item the file belongs to, and either a field value or the attributes of a file to download.
+	itemID  string
+	value   *string
+	file    *FileAttributes
+	content []byte
+}
+
+func (v *vaultFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	node, err := v.lookup(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	if node.dir {
+		return &vaultFSDir{node: node}, nil
+	}
+	content, err := v.content(node)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &vaultFSFile{node: node, Reader: bytes.NewReader(content)}, nil
+}
+
+// lookup walks the tree to the node at the given path, loading the vault and items on the way.
+func (v *vaultFS) lookup(name string) (*vaultFSNode, error) {
+	if v.root == nil {
+		if err := v.loadRoot(); err != nil {
+			return nil, err
+		}
+	}
+	node := v.root
+	if name == "." {
+		return node, nil
+	}
+	for _, elem := range strings.Split(name, "/") {
+		if !node.dir {
+			return nil, fs.ErrNotExist
+		}
+		if err := v.loadItem(node); err != nil {
+			return nil, err
+		}
+		i := slices.IndexFunc(node.children, func(c *vaultFSNode) bool { return c.name == elem })
+		if i < 0 {
+			return nil, fs.ErrNotExist
+		}
+		node = node.children[i]
+	}
+	if err := v.loadItem(node); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+func (v *vaultFS) loadRoot() error {
+	filter := NewItemListFilterTypeVariantByState(&ItemListFilterByStateInner{Active: true})
+	overviews, err := v.client.Items().List(v.ctx, v.vaultID, filter)
+	if err != nil {
+		return err
+	}
+	root := &vaultFSNode{name: ".", dir: true}
+	for _, overview := range overviews {
+		root.add(&vaultFSNode{name: fsName(overview.Title, overview.ID), modTime: overview.UpdatedAt, dir: true, lazyItemID: overview.ID})
+	}
+	v.root = root
+	return nil
+}
+
+// loadItem fills in the directory of an item the first time it's accessed.
+func (v *vaultFS) loadItem(node *vaultFSNode) error {
+	if node.lazyItemID == "" {
+		return nil
+	}
+	item, err := v.client.Items().Get(v.ctx, v.vaultID, node.lazyItemID)
+	if err != nil {
+		return err
+	}
+
+	sections := map[string]*vaultFSNode{}
+	for _, section := range item.Sections {
+		if section.Title != "" && section.ID != fileIntegritySectionID {
+			sections[section.ID] = &vaultFSNode{name: fsName(section.Title, section.ID), modTime: item.UpdatedAt, dir: true}
+		}
+	}
+	parent := func(sectionID *string) *vaultFSNode {
+		if sectionID != nil {
+			if dir, ok := sections[*sectionID]; ok {
+				return dir
+			}
+		}
+		return node
+	}
+
+	for _, field := range item.Fields {
+		if IsFileHashField(field) {
+			continue
+		}
+		value := fieldDisplayValue(field)
+		parent(field.SectionID).add(&vaultFSNode{name: fsName(field.Title, field.ID), modTime: item.UpdatedAt, itemID: item.ID, value: &value})
+	}
+	for _, file := range item.Files {
+		attr := file.Attributes
+		node.add(&vaultFSNode{name: fsName(attr.Name, attr.ID), modTime: item.UpdatedAt, itemID: item.ID, file: &attr})
+	}
+	if item.Document != nil {
+		attr := *item.Document
+		node.add(&vaultFSNode{name: fsName(attr.Name, attr.ID), modTime: item.UpdatedAt, itemID: item.ID, file: &attr})
+	}
+	for _, section := range item.Sections {
+		if dir, ok := sections[section.ID]; ok && len(dir.children) > 0 {
+			node.add(dir)
+		}
+	}
+
+	node.lazyItemID = ""
+	return nil
+}
+
+// content returns the content of a file node, downloading and caching it for attached files.
+func (v *vaultFS) content(node *vaultFSNode) ([]byte, error) {
+	if node.value != nil {
+		return []byte(*node.value), nil
+	}
+	if node.content == nil {
+		content, err := v.client.Items().Files().Read(v.ctx, v.vaultID, node.itemID, *node.file)
+		if err != nil {
+			return nil, err
+		}
+		node.content = content
+	}
+	return node.content, nil
+}
+
+// add appends a child, suffixing its name if another child already has it.
+func (n *vaultFSNode) add(child *vaultFSNode) {
+	base := child.name
+	for i := 2; slices.ContainsFunc(n.children, func(c *vaultFSNode) bool { return c.name == child.name }); i++ {
+		child.name = base + "~" + strconv.Itoa(i)
+	}
+	n.children = append(n.children, child)
+}
+
+// fsName turns a title into a valid file name, falling back to the ID for titles that can't be used.
+func fsName(title string, id string) string {
+	name := strings.ReplaceAll(title, "/", "_")
+	if name == "" || name == "." || name == ".." {
+		return id
+	}
+	return name
+}
+
+func (n *vaultFSNode) Name() string       { return n.name }
+func (n *vaultFSNode) ModTime() time.Time { return n.modTime }
+func (n *vaultFSNode) IsDir() bool        { return n.dir }
+func (n *vaultFSNode) Sys() any           { return nil }
+
+func (n *vaultFSNode) Size() int64 {
+	switch {
+	case n.value != nil:
+		return int64(len(*n.value))
+	case n.file != nil:
+		return int64(n.file.Size)
+	}
+	return 0
+}
+
+func (n *vaultFSNode) Mode() fs.FileMode {
+	if n.dir {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
+
+func (n *vaultFSNode) Type() fs.FileMode          { return n.Mode().Type() }
+func (n *vaultFSNode) Info() (fs.FileInfo, error) { return n, nil }
+
+type vaultFSFile struct {
+	node *vaultFSNode
+	*bytes.Reader
+}
+
+func (f *vaultFSFile) Stat() (fs.FileInfo, error) { return f.node, nil }
+func (f *vaultFSFile) Close() error               { return nil }
+
+type vaultFSDir struct {
+	node   *vaultFSNode
+	offset int
+}
+
+func (d *vaultFSDir) Stat() (fs.FileInfo, error) { return d.node, nil }
+func (d *vaultFSDir) Close() error               { return nil }
+
+func (d *vaultFSDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.node.name, Err: fs.ErrInvalid}
+}
+
+func (d *vaultFSDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := d.node.children[d.offset:]
+	if n > 0 {
+		if len(remaining) == 0 {
+			return nil, io.EOF
+		}
+		remaining = remaining[:min(n, len(remaining))]
+	}
+	entries := make([]fs.DirEntry, len(remaining))
+	for i, child := range remaining {
+		entries[i] = child
+	}
+	d.offset += len(remaining)
+	return entries, nil
+}
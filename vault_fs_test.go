@@ -0,0 +1,85 @@
+package onepassword
+
+import (
+	"context"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeVaultFSItems struct {
+	ItemsAPI
+	items []Item
+	files map[string][]byte
+}
+
+func (f fakeVaultFSItems) List(ctx context.Context, vaultID string, filters ...ItemListFilter) ([]ItemOverview, error) {
+	var overviews []ItemOverview
+	for _, item := range f.items {
+		overviews = append(overviews, ItemOverview{ID: item.ID, Title: item.Title, VaultID: vaultID})
+	}
+	return overviews, nil
+}
+
+func (f fakeVaultFSItems) Get(ctx context.Context, vaultID string, itemID string) (Item, error) {
+	for _, item := range f.items {
+		if item.ID == itemID {
+			return item, nil
+		}
+	}
+	return Item{}, fs.ErrNotExist
+}
+
+func (f fakeVaultFSItems) Files() ItemsFilesAPI {
+	return fakeVaultFSFiles{files: f.files}
+}
+
+type fakeVaultFSFiles struct {
+	ItemsFilesAPI
+	files map[string][]byte
+}
+
+func (f fakeVaultFSFiles) Read(ctx context.Context, vaultID string, itemID string, attr FileAttributes) ([]byte, error) {
+	return f.files[attr.ID], nil
+}
+
+func TestVaultFS(t *testing.T) {
+	connection := "connection"
+	items := fakeVaultFSItems{
+		items: []Item{
+			{
+				ID:       "item1",
+				Title:    "db-prod",
+				Sections: []ItemSection{{ID: "connection", Title: "Connection"}},
+				Fields: []ItemField{
+					{ID: "password", Title: "password", FieldType: ItemFieldTypeConcealed, Value: "hunter2"},
+					{ID: "host", Title: "host", SectionID: &connection, FieldType: ItemFieldTypeText, Value: "db.internal"},
+				},
+			},
+			{
+				ID:    "item2",
+				Title: "tls",
+				Files: []ItemFile{{Attributes: FileAttributes{ID: "file1", Name: "cert.pem", Size: 11}, SectionID: "certs", FieldID: "cert"}},
+			},
+			{ID: "item3", Title: "tls"},
+		},
+		files: map[string][]byte{"file1": []byte("-----BEGIN-")},
+	}
+	fsys := VaultFS(context.Background(), &Client{ItemsAPI: items}, "vault1")
+
+	require.NoError(t, fstest.TestFS(fsys, "db-prod/password", "db-prod/Connection/host", "tls/cert.pem", "tls~2"))
+
+	password, err := fs.ReadFile(fsys, "db-prod/password")
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", string(password))
+
+	cert, err := fs.ReadFile(fsys, "tls/cert.pem")
+	require.NoError(t, err)
+	assert.Equal(t, "-----BEGIN-", string(cert))
+
+	_, err = fsys.Open("db-prod/missing")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
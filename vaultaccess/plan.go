@@ -0,0 +1,218 @@
+package vaultaccess
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/1password/onepassword-sdk-go"
+)
+
+// ChangeType is the kind of change made to a group's access to a vault.
+type ChangeType string
+
+const (
+	ChangeGrant  ChangeType = "grant"
+	ChangeUpdate ChangeType = "update"
+	ChangeRevoke ChangeType = "revoke"
+)
+
+// Change is a change to a group's access to a vault.
+type Change struct {
+	Type       ChangeType
+	VaultID    string
+	VaultTitle string
+	GroupID    string
+	// The group's current permissions, NoAccess for grants
+	From onepassword.Permission
+	// The group's desired permissions, NoAccess for revocations
+	To onepassword.Permission
+}
+
+func (c Change) String() string {
+	switch c.Type {
+	case ChangeGrant:
+		return fmt.Sprintf("grant group %s on vault %q: %s", c.GroupID, c.VaultTitle, c.To)
+	case ChangeUpdate:
+		return fmt.Sprintf("update group %s on vault %q: %s -> %s", c.GroupID, c.VaultTitle, c.From, c.To)
+	default:
+		return fmt.Sprintf("revoke group %s on vault %q: %s", c.GroupID, c.VaultTitle, c.From)
+	}
+}
+
+// Plan is the set of changes needed to bring vault access in line with a desired-state document.
+type Plan struct {
+	Changes []Change
+}
+
+// Empty reports whether the vaults already have the desired access.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// String describes the plan, one change per line.
+func (p *Plan) String() string {
+	if p.Empty() {
+		return "no changes\n"
+	}
+	var b strings.Builder
+	for _, c := range p.Changes {
+		b.WriteString(c.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// NewPlan reads the current group access of every vault in the config and plans the changes needed to reach it.
+func NewPlan(ctx context.Context, client *onepassword.Client, config *Config) (*Plan, error) {
+	vaults, err := client.Vaults().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing vaults: %w", err)
+	}
+
+	plan := &Plan{}
+	seen := map[string]bool{}
+	groupTypes := map[string]onepassword.GroupType{}
+	for _, vaultConfig := range config.Vaults {
+		overview, err := findVault(vaults, vaultConfig.Vault)
+		if err != nil {
+			return nil, err
+		}
+		if seen[overview.ID] {
+			return nil, fmt.Errorf("vault %s is listed more than once", vaultConfig.Vault)
+		}
+		seen[overview.ID] = true
+
+		desired, err := vaultConfig.desiredPermissions()
+		if err != nil {
+			return nil, err
+		}
+
+		accessors := true
+		vault, err := client.Vaults().Get(ctx, overview.ID, onepassword.VaultGetParams{Accessors: &accessors})
+		if err != nil {
+			return nil, fmt.Errorf("error getting access of vault %s: %w", overview.Title, err)
+		}
+		current := map[string]onepassword.Permission{}
+		for _, access := range vault.Access {
+			if access.AccessorType == onepassword.VaultAccessorTypeGroup {
+				current[access.AccessorUuid] = onepassword.Permission(access.Permissions)
+			}
+		}
+
+		for _, group := range vaultConfig.Groups {
+			change := Change{VaultID: vault.ID, VaultTitle: vault.Title, GroupID: group.Group, To: desired[group.Group]}
+			from, ok := current[group.Group]
+			switch {
+			case !ok:
+				change.Type = ChangeGrant
+			case from != change.To:
+				change.Type = ChangeUpdate
+				change.From = from
+			default:
+				continue
+			}
+			plan.Changes = append(plan.Changes, change)
+		}
+
+		if vaultConfig.Exclusive {
+			var extra []string
+			for groupID := range current {
+				if _, ok := desired[groupID]; ok {
+					continue
+				}
+				builtIn, err := isBuiltInGroup(ctx, client, groupTypes, groupID)
+				if err != nil {
+					return nil, err
+				}
+				if !builtIn {
+					extra = append(extra, groupID)
+				}
+			}
+			sort.Strings(extra)
+			for _, groupID := range extra {
+				plan.Changes = append(plan.Changes, Change{Type: ChangeRevoke, VaultID: vault.ID, VaultTitle: vault.Title, GroupID: groupID, From: current[groupID]})
+			}
+		}
+	}
+	return plan, nil
+}
+
+// Apply makes the planned changes: grants are made per vault, updates in a single call and revocations one by one.
+// Failing calls don't stop the others; the returned error lists all failures.
+func (p *Plan) Apply(ctx context.Context, client *onepassword.Client) error {
+	var errs []error
+
+	grants := map[string][]onepassword.GroupAccess{}
+	var grantVaults []string
+	var updates []onepassword.GroupVaultAccess
+	for _, c := range p.Changes {
+		switch c.Type {
+		case ChangeGrant:
+			if _, ok := grants[c.VaultID]; !ok {
+				grantVaults = append(grantVaults, c.VaultID)
+			}
+			grants[c.VaultID] = append(grants[c.VaultID], onepassword.GroupAccess{GroupID: c.GroupID, Permissions: uint32(c.To)})
+		case ChangeUpdate:
+			updates = append(updates, onepassword.GroupVaultAccess{VaultID: c.VaultID, GroupID: c.GroupID, Permissions: uint32(c.To)})
+		}
+	}
+
+	for _, vaultID := range grantVaults {
+		if err := client.Vaults().GrantGroupPermissions(ctx, vaultID, grants[vaultID]); err != nil {
+			errs = append(errs, fmt.Errorf("error granting group permissions on vault %s: %w", vaultID, err))
+		}
+	}
+	if len(updates) > 0 {
+		if err := client.Vaults().UpdateGroupPermissions(ctx, updates); err != nil {
+			errs = append(errs, fmt.Errorf("error updating group permissions: %w", err))
+		}
+	}
+	for _, c := range p.Changes {
+		if c.Type != ChangeRevoke {
+			continue
+		}
+		if err := client.Vaults().RevokeGroupPermissions(ctx, c.VaultID, c.GroupID); err != nil {
+			errs = append(errs, fmt.Errorf("error revoking permissions of group %s on vault %s: %w", c.GroupID, c.VaultID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// isBuiltInGroup reports whether a group is one of the groups every account has, such as Owners and Administrators,
+// rather than a group created by its members. Group types are looked up once and cached in types.
+func isBuiltInGroup(ctx context.Context, client *onepassword.Client, types map[string]onepassword.GroupType, groupID string) (bool, error) {
+	groupType, ok := types[groupID]
+	if !ok {
+		group, err := client.Groups().Get(ctx, groupID, onepassword.GroupGetParams{})
+		if err != nil {
+			return false, fmt.Errorf("error getting group %s: %w", groupID, err)
+		}
+		groupType = group.GroupType
+		types[groupID] = groupType
+	}
+	return groupType != onepassword.GroupTypeUserDefined, nil
+}
+
+// findVault looks up a vault by ID, or else by title, which must then be unique.
+func findVault(vaults []onepassword.VaultOverview, ref string) (onepassword.VaultOverview, error) {
+	var matches []onepassword.VaultOverview
+	for _, v := range vaults {
+		if v.ID == ref {
+			return v, nil
+		}
+		if v.Title == ref {
+			matches = append(matches, v)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return onepassword.VaultOverview{}, fmt.Errorf("vault %s not found", ref)
+	case 1:
+		return matches[0], nil
+	default:
+		return onepassword.VaultOverview{}, fmt.Errorf("%d vaults are titled %q, use the vault ID instead", len(matches), ref)
+	}
+}
@@ -0,0 +1,83 @@
+// Package vaultaccess manages the groups that have access to vaults as code.
+//
+// A desired-state document lists vaults and the permissions each group should have on them:
+//
+//	vaults:
+//	  - vault: Production        # vault ID or title
+//	    exclusive: true          # revoke the access of groups that aren't listed
+//	    groups:
+//	      - group: abcdefghijklmnopqrstuvwxyz
+//	        permissions: view|send_items
+//
+// Permissions use the format of onepassword.ParsePermissions. NewPlan compares the document with the current access
+// of each vault and describes the grants, updates and revocations needed, without changing anything; Plan.Apply
+// makes those changes. Access granted to individual users is left untouched.
+package vaultaccess
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/1password/onepassword-sdk-go"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the desired access to a set of vaults.
+type Config struct {
+	Vaults []VaultConfig `yaml:"vaults" json:"vaults"`
+}
+
+// VaultConfig is the desired access to a vault.
+type VaultConfig struct {
+	// The ID or title of the vault
+	Vault string `yaml:"vault" json:"vault"`
+	// Revoke the access of groups that aren't listed. Built-in groups, such as Owners and Administrators, keep
+	// their access unless they're listed.
+	Exclusive bool `yaml:"exclusive" json:"exclusive"`
+	// The groups that should have access to the vault
+	Groups []GroupConfig `yaml:"groups" json:"groups"`
+}
+
+// GroupConfig is the desired access of a group to a vault.
+type GroupConfig struct {
+	// The ID of the group
+	Group string `yaml:"group" json:"group"`
+	// The permissions of the group, e.g. "view|send_items" or "read_items|reveal_item_password"
+	Permissions string `yaml:"permissions" json:"permissions"`
+}
+
+// Load reads a desired-state document in YAML or JSON.
+func Load(r io.Reader) (*Config, error) {
+	var config Config
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("error parsing vault access document: %w", err)
+	}
+	return &config, nil
+}
+
+// desiredPermissions parses and validates the permissions of the groups of a vault.
+func (v VaultConfig) desiredPermissions() (map[string]onepassword.Permission, error) {
+	permissions := map[string]onepassword.Permission{}
+	for _, group := range v.Groups {
+		if group.Group == "" {
+			return nil, fmt.Errorf("vault %s: a group has no ID", v.Vault)
+		}
+		if _, ok := permissions[group.Group]; ok {
+			return nil, fmt.Errorf("vault %s: group %s is listed more than once", v.Vault, group.Group)
+		}
+		p, err := onepassword.ParsePermissions(group.Permissions)
+		if err != nil {
+			return nil, fmt.Errorf("vault %s, group %s: %w", v.Vault, group.Group, err)
+		}
+		if err := p.Validate(); err != nil {
+			return nil, fmt.Errorf("vault %s, group %s: %w", v.Vault, group.Group, err)
+		}
+		if uint32(p) == onepassword.NoAccess {
+			return nil, fmt.Errorf("vault %s, group %s: no permissions given, remove the group to revoke its access", v.Vault, group.Group)
+		}
+		permissions[group.Group] = p
+	}
+	return permissions, nil
+}
@@ -0,0 +1,124 @@
+package vaultaccess
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/1password/onepassword-sdk-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeVaults struct {
+	onepassword.VaultsAPI
+	vault   onepassword.Vault
+	granted []onepassword.GroupAccess
+	updated []onepassword.GroupVaultAccess
+	revoked []string
+}
+
+func (f *fakeVaults) List(ctx context.Context, params ...onepassword.VaultListParams) ([]onepassword.VaultOverview, error) {
+	return []onepassword.VaultOverview{{ID: f.vault.ID, Title: f.vault.Title}}, nil
+}
+
+func (f *fakeVaults) Get(ctx context.Context, vaultID string, params onepassword.VaultGetParams) (onepassword.Vault, error) {
+	return f.vault, nil
+}
+
+func (f *fakeVaults) GrantGroupPermissions(ctx context.Context, vaultID string, access []onepassword.GroupAccess) error {
+	f.granted = append(f.granted, access...)
+	return nil
+}
+
+func (f *fakeVaults) UpdateGroupPermissions(ctx context.Context, access []onepassword.GroupVaultAccess) error {
+	f.updated = append(f.updated, access...)
+	return nil
+}
+
+func (f *fakeVaults) RevokeGroupPermissions(ctx context.Context, vaultID string, groupID string) error {
+	f.revoked = append(f.revoked, groupID)
+	return nil
+}
+
+type fakeGroups struct {
+	onepassword.GroupsAPI
+	types map[string]onepassword.GroupType
+}
+
+func (f fakeGroups) Get(ctx context.Context, groupID string, params onepassword.GroupGetParams) (onepassword.Group, error) {
+	groupType, ok := f.types[groupID]
+	if !ok {
+		groupType = onepassword.GroupTypeUserDefined
+	}
+	return onepassword.Group{ID: groupID, GroupType: groupType}, nil
+}
+
+func TestReconcile(t *testing.T) {
+	config, err := Load(strings.NewReader(`
+vaults:
+  - vault: Production
+    exclusive: true
+    groups:
+      - group: ops
+        permissions: manage
+      - group: devs
+        permissions: view
+      - group: auditors
+        permissions: read_items
+`))
+	require.NoError(t, err)
+
+	vaults := &fakeVaults{vault: onepassword.Vault{
+		ID:    "vault1",
+		Title: "Production",
+		Access: []onepassword.VaultAccess{
+			{AccessorType: onepassword.VaultAccessorTypeGroup, AccessorUuid: "ops", Permissions: onepassword.ManagePermissions},
+			{AccessorType: onepassword.VaultAccessorTypeGroup, AccessorUuid: "devs", Permissions: onepassword.EditPermissions},
+			{AccessorType: onepassword.VaultAccessorTypeGroup, AccessorUuid: "interns", Permissions: onepassword.ViewPermissions},
+			{AccessorType: onepassword.VaultAccessorTypeUser, AccessorUuid: "jane", Permissions: onepassword.ViewPermissions},
+		},
+	}}
+	client := &onepassword.Client{VaultsAPI: vaults, GroupsAPI: fakeGroups{}}
+
+	plan, err := NewPlan(context.Background(), client, config)
+	require.NoError(t, err)
+	assert.Equal(t, `update group devs on vault "Production": `+onepassword.Permission(onepassword.EditPermissions).String()+` -> `+onepassword.Permission(onepassword.ViewPermissions).String()+`
+grant group auditors on vault "Production": read_items
+revoke group interns on vault "Production": `+onepassword.Permission(onepassword.ViewPermissions).String()+`
+`, plan.String())
+
+	require.NoError(t, plan.Apply(context.Background(), client))
+	assert.Equal(t, []onepassword.GroupAccess{{GroupID: "auditors", Permissions: onepassword.ReadItems}}, vaults.granted)
+	assert.Equal(t, []onepassword.GroupVaultAccess{{VaultID: "vault1", GroupID: "devs", Permissions: onepassword.ViewPermissions}}, vaults.updated)
+	assert.Equal(t, []string{"interns"}, vaults.revoked)
+
+	_, err = NewPlan(context.Background(), client, &Config{Vaults: []VaultConfig{{Vault: "Production", Groups: []GroupConfig{{Group: "devs", Permissions: "update_items"}}}}})
+	assert.ErrorContains(t, err, "update_items requires")
+}
+
+func TestExclusiveKeepsBuiltInGroups(t *testing.T) {
+	vaults := &fakeVaults{vault: onepassword.Vault{
+		ID:    "vault1",
+		Title: "Production",
+		Access: []onepassword.VaultAccess{
+			{AccessorType: onepassword.VaultAccessorTypeGroup, AccessorUuid: "owners", Permissions: onepassword.ManagePermissions},
+			{AccessorType: onepassword.VaultAccessorTypeGroup, AccessorUuid: "admins", Permissions: onepassword.ManagePermissions},
+			{AccessorType: onepassword.VaultAccessorTypeGroup, AccessorUuid: "interns", Permissions: onepassword.ViewPermissions},
+		},
+	}}
+	client := &onepassword.Client{VaultsAPI: vaults, GroupsAPI: fakeGroups{types: map[string]onepassword.GroupType{
+		"owners": onepassword.GroupTypeOwners,
+		"admins": onepassword.GroupTypeAdministrators,
+	}}}
+
+	plan, err := NewPlan(context.Background(), client, &Config{Vaults: []VaultConfig{{Vault: "Production", Exclusive: true}}})
+	require.NoError(t, err)
+	assert.Equal(t, `revoke group interns on vault "Production": `+onepassword.Permission(onepassword.ViewPermissions).String()+"\n", plan.String())
+
+	// Listed built-in groups are managed like any other group.
+	plan, err = NewPlan(context.Background(), client, &Config{Vaults: []VaultConfig{{Vault: "Production", Exclusive: true, Groups: []GroupConfig{{Group: "admins", Permissions: "view"}}}}})
+	require.NoError(t, err)
+	assert.Equal(t, `update group admins on vault "Production": `+onepassword.Permission(onepassword.ManagePermissions).String()+` -> `+onepassword.Permission(onepassword.ViewPermissions).String()+`
+revoke group interns on vault "Production": `+onepassword.Permission(onepassword.ViewPermissions).String()+"\n", plan.String())
+}
@@ -0,0 +1,181 @@
+// Package vaultreport builds an inventory of the vaults of an account, for access reviews and audits.
+//
+// Inventory combines the vault overviews with the access list of each vault; WriteCSV and WriteJSON write it out
+// with the permissions of each accessor decoded into their names.
+package vaultreport
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/1password/onepassword-sdk-go"
+)
+
+// Vault is the inventory entry of a vault.
+type Vault struct {
+	ID             string                `json:"id"`
+	Title          string                `json:"title"`
+	Description    string                `json:"description,omitempty"`
+	Type           onepassword.VaultType `json:"type"`
+	ItemCount      uint32                `json:"itemCount"`
+	ContentVersion uint32                `json:"contentVersion"`
+	Accessors      []Accessor            `json:"accessors"`
+	CreatedAt      time.Time             `json:"createdAt"`
+	UpdatedAt      time.Time             `json:"updatedAt"`
+}
+
+// Accessor is a user or group with access to a vault.
+type Accessor struct {
+	Type onepassword.VaultAccessorType `json:"type"`
+	ID   string                        `json:"id"`
+	// The name of the user or title of the group, only set if Options.ResolveNames is
+	Name        string                 `json:"name,omitempty"`
+	Permissions onepassword.Permission `json:"-"`
+}
+
+// MarshalJSON encodes the permissions by their names, e.g. ["read_items","reveal_item_password"].
+func (a Accessor) MarshalJSON() ([]byte, error) {
+	type accessor Accessor
+	return json.Marshal(struct {
+		accessor
+		Permissions []string `json:"permissions"`
+	}{accessor(a), permissionNames(a.Permissions)})
+}
+
+// Options configures Inventory.
+type Options struct {
+	// Look up the names of the users and groups with access to the vaults
+	ResolveNames bool
+}
+
+// Inventory lists the vaults the client can access together with the users and groups that have access to each.
+func Inventory(ctx context.Context, client *onepassword.Client, opts Options) ([]Vault, error) {
+	decryptDetails := true
+	overviews, err := client.Vaults().List(ctx, onepassword.VaultListParams{DecryptDetails: &decryptDetails})
+	if err != nil {
+		return nil, fmt.Errorf("error listing vaults: %w", err)
+	}
+
+	var names map[string]string
+	if opts.ResolveNames {
+		names, err = accessorNames(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	accessors := true
+	inventory := make([]Vault, 0, len(overviews))
+	for _, overview := range overviews {
+		vault, err := client.Vaults().Get(ctx, overview.ID, onepassword.VaultGetParams{Accessors: &accessors})
+		if err != nil {
+			return nil, fmt.Errorf("error getting access of vault %s: %w", overview.Title, err)
+		}
+		entry := Vault{
+			ID:             overview.ID,
+			Title:          overview.Title,
+			Description:    overview.Description,
+			Type:           overview.VaultType,
+			ItemCount:      overview.ActiveItemCount,
+			ContentVersion: overview.ContentVersion,
+			Accessors:      []Accessor{},
+			CreatedAt:      overview.CreatedAt,
+			UpdatedAt:      overview.UpdatedAt,
+		}
+		for _, access := range vault.Access {
+			entry.Accessors = append(entry.Accessors, Accessor{
+				Type:        access.AccessorType,
+				ID:          access.AccessorUuid,
+				Name:        names[string(access.AccessorType)+"/"+access.AccessorUuid],
+				Permissions: onepassword.Permission(access.Permissions),
+			})
+		}
+		inventory = append(inventory, entry)
+	}
+	return inventory, nil
+}
+
+// accessorNames maps "user/<ID>" and "group/<ID>" to the name of every user and the title of every group.
+func accessorNames(ctx context.Context, client *onepassword.Client) (map[string]string, error) {
+	names := map[string]string{}
+	users, err := client.Users().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing users: %w", err)
+	}
+	for _, u := range users {
+		names[string(onepassword.VaultAccessorTypeUser)+"/"+u.ID] = u.Name
+	}
+	groups, err := client.Groups().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing groups: %w", err)
+	}
+	for _, g := range groups {
+		names[string(onepassword.VaultAccessorTypeGroup)+"/"+g.ID] = g.Title
+	}
+	return names, nil
+}
+
+// WriteJSON writes the inventory as an indented JSON array.
+func WriteJSON(w io.Writer, inventory []Vault) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(inventory)
+}
+
+// csvHeader is the header row written by WriteCSV.
+var csvHeader = []string{"vault_id", "title", "type", "item_count", "content_version", "created_at", "updated_at", "accessor_type", "accessor_id", "accessor_name", "permissions"}
+
+// WriteCSV writes the inventory as CSV with one row per vault accessor, so it can be filtered and sorted in a
+// spreadsheet. Vaults without accessors get a single row with empty accessor columns. Permissions are separated by "|".
+func WriteCSV(w io.Writer, inventory []Vault) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, v := range inventory {
+		vault := []string{
+			v.ID,
+			v.Title,
+			string(v.Type),
+			strconv.FormatUint(uint64(v.ItemCount), 10),
+			strconv.FormatUint(uint64(v.ContentVersion), 10),
+			formatTime(v.CreatedAt),
+			formatTime(v.UpdatedAt),
+		}
+		if len(v.Accessors) == 0 {
+			if err := writer.Write(append(vault, "", "", "", "")); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, a := range v.Accessors {
+			row := append(append([]string{}, vault...), string(a.Type), a.ID, a.Name, strings.Join(permissionNames(a.Permissions), "|"))
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// permissionNames splits the permissions into their names, in the order of onepassword.Permission.String.
+func permissionNames(p onepassword.Permission) []string {
+	if uint32(p) == onepassword.NoAccess {
+		return []string{}
+	}
+	return strings.Split(p.String(), "|")
+}
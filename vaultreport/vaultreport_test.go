@@ -0,0 +1,72 @@
+package vaultreport
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/1password/onepassword-sdk-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeVaults struct {
+	onepassword.VaultsAPI
+}
+
+func (fakeVaults) List(ctx context.Context, params ...onepassword.VaultListParams) ([]onepassword.VaultOverview, error) {
+	return []onepassword.VaultOverview{
+		{ID: "vault1", Title: "Production", VaultType: onepassword.VaultTypeUserCreated, ActiveItemCount: 12, ContentVersion: 40, CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{ID: "vault2", Title: "Empty", VaultType: onepassword.VaultTypeUserCreated},
+	}, nil
+}
+
+func (fakeVaults) Get(ctx context.Context, vaultID string, params onepassword.VaultGetParams) (onepassword.Vault, error) {
+	if vaultID != "vault1" {
+		return onepassword.Vault{ID: vaultID}, nil
+	}
+	return onepassword.Vault{ID: vaultID, Access: []onepassword.VaultAccess{
+		{AccessorType: onepassword.VaultAccessorTypeGroup, AccessorUuid: "ops", Permissions: onepassword.ReadItems | onepassword.RevealItemPassword},
+		{AccessorType: onepassword.VaultAccessorTypeUser, AccessorUuid: "jane", Permissions: onepassword.ReadItems},
+	}}, nil
+}
+
+type fakeUsers struct {
+	onepassword.UsersAPI
+}
+
+func (fakeUsers) List(ctx context.Context, params ...onepassword.UserListParams) ([]onepassword.User, error) {
+	return []onepassword.User{{ID: "jane", Name: "Jane Doe"}}, nil
+}
+
+type fakeGroups struct {
+	onepassword.GroupsAPI
+}
+
+func (fakeGroups) List(ctx context.Context, params ...onepassword.GroupListParams) ([]onepassword.Group, error) {
+	return []onepassword.Group{{ID: "ops", Title: "Operations"}}, nil
+}
+
+func TestInventory(t *testing.T) {
+	client := &onepassword.Client{VaultsAPI: fakeVaults{}, UsersAPI: fakeUsers{}, GroupsAPI: fakeGroups{}}
+	inventory, err := Inventory(context.Background(), client, Options{ResolveNames: true})
+	require.NoError(t, err)
+	require.Len(t, inventory, 2)
+	assert.Equal(t, "Operations", inventory[0].Accessors[0].Name)
+
+	var csv bytes.Buffer
+	require.NoError(t, WriteCSV(&csv, inventory))
+	assert.Equal(t, `vault_id,title,type,item_count,content_version,created_at,updated_at,accessor_type,accessor_id,accessor_name,permissions
+vault1,Production,userCreated,12,40,2024-01-02T03:04:05Z,,group,ops,Operations,read_items|reveal_item_password
+vault1,Production,userCreated,12,40,2024-01-02T03:04:05Z,,user,jane,Jane Doe,read_items
+vault2,Empty,userCreated,0,0,,,,,,
+`, csv.String())
+
+	var js bytes.Buffer
+	require.NoError(t, WriteJSON(&js, inventory[:1]))
+	assert.Contains(t, js.String(), `"permissions": [
+          "read_items",
+          "reveal_item_password"
+        ]`)
+}
@@ -29,6 +29,12 @@ type VaultsAPI interface {
 	// Delete a vault by its ID.
 	Delete(ctx context.Context, vaultID string) error
 
+	// Archive a vault by its ID. Archived vaults can be restored. Returns an error if the account doesn't support archiving vaults.
+	Archive(ctx context.Context, vaultID string) error
+
+	// Restore an archived vault by its ID.
+	Restore(ctx context.Context, vaultID string) error
+
 	// Grant group permissions to a vault.
 	GrantGroupPermissions(ctx context.Context, vaultID string, groupPermissionsList []GroupAccess) error
 
@@ -37,6 +43,15 @@ type VaultsAPI interface {
 
 	// Revoke group permissions from a vault.
 	RevokeGroupPermissions(ctx context.Context, vaultID string, groupID string) error
+
+	// Grant user permissions to a vault.
+	GrantUserPermissions(ctx context.Context, vaultID string, userPermissionsList []UserAccess) error
+
+	// Update user permissions for vaults.
+	UpdateUserPermissions(ctx context.Context, userPermissionsList []UserVaultAccess) error
+
+	// Revoke user permissions from a vault.
+	RevokeUserPermissions(ctx context.Context, vaultID string, userID string) error
 }
 
 type VaultsSource struct {
@@ -0,0 +1,21 @@
+package onepassword
+
+import (
+	"context"
+)
+
+// Archive a vault by its ID. Archived vaults can be restored. Returns an error if the account doesn't support archiving vaults.
+func (v VaultsSource) Archive(ctx context.Context, vaultID string) error {
+	_, err := clientInvoke(ctx, v.InnerClient, "VaultsArchive", map[string]interface{}{
+		"vault_id": vaultID,
+	})
+	return err
+}
+
+// Restore an archived vault by its ID.
+func (v VaultsSource) Restore(ctx context.Context, vaultID string) error {
+	_, err := clientInvoke(ctx, v.InnerClient, "VaultsRestore", map[string]interface{}{
+		"vault_id": vaultID,
+	})
+	return err
+}
@@ -0,0 +1,50 @@
+package onepassword
+
+import (
+	"context"
+)
+
+// Represents a user's access to a 1Password vault.
+// This is used for granting permissions
+type UserAccess struct {
+	// The user's ID
+	UserID string `json:"userId"`
+	// The user's set of permissions for the vault
+	Permissions uint32 `json:"permissions"`
+}
+
+// Represents a user's access to a 1Password vault.
+type UserVaultAccess struct {
+	// The vault's ID
+	VaultID string `json:"vaultId"`
+	// The user's ID
+	UserID string `json:"userId"`
+	// The user's set of permissions for the vault
+	Permissions uint32 `json:"permissions"`
+}
+
+// Grant user permissions to a vault.
+func (v VaultsSource) GrantUserPermissions(ctx context.Context, vaultID string, userPermissionsList []UserAccess) error {
+	_, err := clientInvoke(ctx, v.InnerClient, "VaultsGrantUserPermissions", map[string]interface{}{
+		"vault_id":              vaultID,
+		"user_permissions_list": userPermissionsList,
+	})
+	return err
+}
+
+// Update user permissions for vaults.
+func (v VaultsSource) UpdateUserPermissions(ctx context.Context, userPermissionsList []UserVaultAccess) error {
+	_, err := clientInvoke(ctx, v.InnerClient, "VaultsUpdateUserPermissions", map[string]interface{}{
+		"user_permissions_list": userPermissionsList,
+	})
+	return err
+}
+
+// Revoke user permissions from a vault.
+func (v VaultsSource) RevokeUserPermissions(ctx context.Context, vaultID string, userID string) error {
+	_, err := clientInvoke(ctx, v.InnerClient, "VaultsRevokeUserPermissions", map[string]interface{}{
+		"vault_id": vaultID,
+		"user_id":  userID,
+	})
+	return err
+}
@@ -0,0 +1,28 @@
+package onepassword
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultUserPermissions(t *testing.T) {
+	ctx := context.Background()
+	core := &fakeRecordingCore{}
+	vaults := NewVaultsSource(core.inner())
+
+	require.NoError(t, vaults.GrantUserPermissions(ctx, "vault1", []UserAccess{{UserID: "user1", Permissions: ReadItems | RevealItemPassword}}))
+	core.assertInvoked(t, "VaultsGrantUserPermissions", `{
+		"vault_id": "vault1",
+		"user_permissions_list": [{"userId": "user1", "permissions": 48}]
+	}`)
+
+	require.NoError(t, vaults.UpdateUserPermissions(ctx, []UserVaultAccess{{VaultID: "vault1", UserID: "user1", Permissions: ManageVault}}))
+	core.assertInvoked(t, "VaultsUpdateUserPermissions", `{
+		"user_permissions_list": [{"vaultId": "vault1", "userId": "user1", "permissions": 2}]
+	}`)
+
+	require.NoError(t, vaults.RevokeUserPermissions(ctx, "vault1", "user1"))
+	core.assertInvoked(t, "VaultsRevokeUserPermissions", `{"vault_id": "vault1", "user_id": "user1"}`)
+}
@@ -0,0 +1,327 @@
+package vaultsync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+
+	"github.com/1password/onepassword-sdk-go"
+)
+
+// Failure is an action that couldn't be carried out.
+type Failure struct {
+	Action Action
+	Err    error
+}
+
+func (f Failure) Error() string {
+	return fmt.Sprintf("error applying %s: %s", f.Action, f.Err)
+}
+
+func (f Failure) Unwrap() error {
+	return f.Err
+}
+
+// Report describes the outcome of applying a plan.
+type Report struct {
+	// The destination items that were created
+	Created []onepassword.Item
+	// The destination items that were updated
+	Updated []onepassword.Item
+	// The IDs of the destination items that were archived or deleted
+	Removed []string
+	// The actions that failed
+	Failed []Failure
+}
+
+// Apply carries out the plan: it creates the missing destination items, updates the changed ones and archives or
+// deletes the extra ones, in that order. A failing action doesn't stop the others, it's recorded in the report.
+// Updates fail with an IncorrectItemVersionError if the destination item changed since the plan was made.
+// An error is only returned if the context is done before all actions were attempted.
+func (p *Plan) Apply(ctx context.Context) (*Report, error) {
+	report := &Report{}
+
+	var creates []Action
+	var params []onepassword.ItemCreateParams
+	for _, action := range p.Actions {
+		if action.Type != ActionCreate {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		source := action.source
+		createParams, err := onepassword.ItemCreateParamsFromItem(ctx, source, func(ctx context.Context, attr onepassword.FileAttributes) ([]byte, error) {
+			return onepassword.ReadFileVerified(ctx, p.Source.Client.Items().Files(), source, attr)
+		})
+		if err != nil {
+			report.Failed = append(report.Failed, Failure{Action: action, Err: err})
+			continue
+		}
+		createParams.VaultID = p.Destination.VaultID
+		createParams.Tags = p.tags(action.source)
+		creates = append(creates, action)
+		params = append(params, createParams)
+	}
+	if len(params) > 0 {
+		// Failing batches don't drop the results of the completed ones: every item has its own response.
+		response, err := p.Destination.Client.Items().CreateAll(ctx, p.Destination.VaultID, params)
+		if err != nil && len(response.IndividualResponses) != len(creates) {
+			for _, action := range creates {
+				report.Failed = append(report.Failed, Failure{Action: action, Err: err})
+			}
+		}
+		for i, res := range response.IndividualResponses {
+			switch {
+			case res.Error != nil && res.Error.Type == onepassword.ItemUpdateFailureReasonTypeVariantInternal:
+				report.Failed = append(report.Failed, Failure{Action: creates[i], Err: fmt.Errorf("%s: %s", res.Error.Type, res.Error.Internal())})
+			case res.Error != nil:
+				report.Failed = append(report.Failed, Failure{Action: creates[i], Err: fmt.Errorf("%s", res.Error.Type)})
+			case res.Content != nil:
+				report.Created = append(report.Created, *res.Content)
+			}
+		}
+		if ctx.Err() != nil {
+			return report, ctx.Err()
+		}
+	}
+
+	for _, action := range p.Actions {
+		if action.Type != ActionUpdate {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		updated, err := p.update(ctx, action)
+		if err != nil {
+			report.Failed = append(report.Failed, Failure{Action: action, Err: err})
+			continue
+		}
+		report.Updated = append(report.Updated, updated)
+	}
+
+	for _, action := range p.Actions {
+		if action.Type != ActionArchive && action.Type != ActionDelete {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		var err error
+		if action.Type == ActionArchive {
+			err = p.Destination.Client.Items().Archive(ctx, p.Destination.VaultID, action.DestinationItemID)
+		} else {
+			err = p.Destination.Client.Items().Delete(ctx, p.Destination.VaultID, action.DestinationItemID)
+		}
+		if err != nil {
+			report.Failed = append(report.Failed, Failure{Action: action, Err: err})
+			continue
+		}
+		report.Removed = append(report.Removed, action.DestinationItemID)
+	}
+
+	return report, nil
+}
+
+// update saves the source item's contents into the destination item, then replaces the files and document whose
+// content differs.
+func (p *Plan) update(ctx context.Context, action Action) (onepassword.Item, error) {
+	items := p.Destination.Client.Items()
+	current := action.destination
+	desired := p.desired(action.source, current, action.files)
+
+	metadataChanged := slices.ContainsFunc(action.Diff.Changes, func(c onepassword.ItemChange) bool {
+		return c.Target != onepassword.ItemChangeTargetFile && c.Target != onepassword.ItemChangeTargetDocument
+	})
+	if metadataChanged {
+		item := desired
+		item.Files = current.Files
+		item.Document = current.Document
+		updated, err := items.Put(ctx, item)
+		if err != nil {
+			return onepassword.Item{}, err
+		}
+		current = updated
+	}
+
+	for _, file := range action.destination.Files {
+		if slices.ContainsFunc(desired.Files, func(f onepassword.ItemFile) bool { return f.Attributes.ID == file.Attributes.ID }) {
+			continue
+		}
+		updated, err := items.Files().Delete(ctx, current, file.SectionID, file.FieldID)
+		if err != nil {
+			return onepassword.Item{}, fmt.Errorf("error deleting file %q: %w", file.Attributes.Name, err)
+		}
+		current = updated
+	}
+
+	for _, file := range desired.Files {
+		if slices.ContainsFunc(action.destination.Files, func(f onepassword.ItemFile) bool { return f.Attributes.ID == file.Attributes.ID }) {
+			continue
+		}
+		content, err := p.Source.Client.Items().Files().Read(ctx, p.Source.VaultID, action.source.ID, file.Attributes)
+		if err != nil {
+			return onepassword.Item{}, fmt.Errorf("error reading file %q: %w", file.Attributes.Name, err)
+		}
+		updated, err := items.Files().Attach(ctx, current, onepassword.FileCreateParams{
+			Name:      file.Attributes.Name,
+			Content:   content,
+			SectionID: file.SectionID,
+			FieldID:   file.FieldID,
+		})
+		if err != nil {
+			return onepassword.Item{}, fmt.Errorf("error attaching file %q: %w", file.Attributes.Name, err)
+		}
+		current = updated
+	}
+
+	if doc := desired.Document; doc != nil && (action.destination.Document == nil || doc.ID != action.destination.Document.ID) {
+		content, err := p.Source.Client.Items().Files().Read(ctx, p.Source.VaultID, action.source.ID, *doc)
+		if err != nil {
+			return onepassword.Item{}, fmt.Errorf("error reading document %q: %w", doc.Name, err)
+		}
+		updated, err := items.Files().ReplaceDocument(ctx, current, onepassword.DocumentCreateParams{Name: doc.Name, Content: content})
+		if err != nil {
+			return onepassword.Item{}, fmt.Errorf("error replacing document %q: %w", doc.Name, err)
+		}
+		current = updated
+	}
+
+	return current, nil
+}
+
+// desired returns the destination item as it should be after syncing it with the source item.
+// Destination files and the destination document that match a source file, see matchFiles, are kept, the others are
+// replaced by the source files.
+func (p *Plan) desired(source onepassword.Item, destination onepassword.Item, files map[string]onepassword.FileAttributes) onepassword.Item {
+	item := destination
+	item.Title = source.Title
+	item.Notes = source.Notes
+	item.Sections = slices.Clone(source.Sections)
+	item.Tags = p.tags(source)
+	item.Websites = slices.Clone(source.Websites)
+
+	item.Fields = nil
+	for _, field := range source.Fields {
+		// OTP codes and SSH key attributes are computed from the field value, only addresses must be carried over.
+		if field.Details != nil && field.Details.Type != onepassword.ItemFieldDetailsTypeVariantAddress {
+			field.Details = nil
+		}
+		item.Fields = append(item.Fields, field)
+	}
+
+	item.Files = nil
+	for _, file := range source.Files {
+		if attr, ok := files[file.Attributes.ID]; ok {
+			file.Attributes = attr
+		}
+		item.Files = append(item.Files, file)
+	}
+
+	item.Document = source.Document
+	if source.Document != nil {
+		if attr, ok := files[source.Document.ID]; ok {
+			item.Document = &attr
+		}
+	}
+
+	return item
+}
+
+// matchFiles returns the files and document of the destination item that hold the same content as those of the
+// source item, by the ID of the source file they match. File IDs differ between vaults, so files are matched by
+// location, name, size and SHA-256 hash of their content.
+func matchFiles(ctx context.Context, source Vault, sourceItem onepassword.Item, destination Vault, destinationItem onepassword.Item) (map[string]onepassword.FileAttributes, error) {
+	files := map[string]onepassword.FileAttributes{}
+	match := func(sourceAttr onepassword.FileAttributes, destinationAttr onepassword.FileAttributes) error {
+		if sourceAttr.Name != destinationAttr.Name || sourceAttr.Size != destinationAttr.Size {
+			return nil
+		}
+		sourceHash, err := fileHash(ctx, source, sourceItem, sourceAttr)
+		if err != nil {
+			return err
+		}
+		destinationHash, err := fileHash(ctx, destination, destinationItem, destinationAttr)
+		if err != nil {
+			return err
+		}
+		if sourceHash == destinationHash {
+			files[sourceAttr.ID] = destinationAttr
+		}
+		return nil
+	}
+
+	for _, file := range sourceItem.Files {
+		i := slices.IndexFunc(destinationItem.Files, func(f onepassword.ItemFile) bool {
+			return f.SectionID == file.SectionID && f.FieldID == file.FieldID
+		})
+		if i < 0 {
+			continue
+		}
+		if err := match(file.Attributes, destinationItem.Files[i].Attributes); err != nil {
+			return nil, err
+		}
+	}
+	if sourceItem.Document != nil && destinationItem.Document != nil {
+		if err := match(*sourceItem.Document, *destinationItem.Document); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// fileHash returns the hex encoded SHA-256 hash of the content of a file, as recorded in the item or otherwise
+// computed from the content read from the vault.
+func fileHash(ctx context.Context, vault Vault, item onepassword.Item, attr onepassword.FileAttributes) (string, error) {
+	if hash, ok := onepassword.FileHash(item, attr); ok {
+		return hash, nil
+	}
+	content, err := vault.Client.Items().Files().Read(ctx, vault.VaultID, item.ID, attr)
+	if err != nil {
+		return "", fmt.Errorf("error reading file %q of item %s in vault %s: %w", attr.Name, item.ID, vault.VaultID, err)
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// tags returns the tags of the destination copy of an item, including its ID tag.
+func (p *Plan) tags(source onepassword.Item) []string {
+	tags := slices.Clone(source.Tags)
+	if p.opts.IDTagPrefix != "" {
+		tags = append(tags, p.opts.IDTagPrefix+source.ID)
+	}
+	return tags
+}
+
+// getItems returns the active items of a vault.
+func getItems(ctx context.Context, vault Vault) ([]onepassword.Item, error) {
+	filter := onepassword.NewItemListFilterTypeVariantByState(&onepassword.ItemListFilterByStateInner{Active: true})
+	overviews, err := vault.Client.Items().List(ctx, vault.VaultID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("error listing items of vault %s: %w", vault.VaultID, err)
+	}
+	if len(overviews) == 0 {
+		return nil, nil
+	}
+
+	itemIDs := make([]string, len(overviews))
+	for i, overview := range overviews {
+		itemIDs[i] = overview.ID
+	}
+	response, err := vault.Client.Items().GetAll(ctx, vault.VaultID, itemIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error getting items of vault %s: %w", vault.VaultID, err)
+	}
+
+	items := make([]onepassword.Item, 0, len(response.IndividualResponses))
+	for i, res := range response.IndividualResponses {
+		if res.Error != nil {
+			return nil, fmt.Errorf("error getting item %s of vault %s: %s", itemIDs[i], vault.VaultID, res.Error.Type)
+		}
+		items = append(items, *res.Content)
+	}
+	return items, nil
+}
@@ -0,0 +1,276 @@
+// Package vaultsync mirrors the items of a source vault into a destination vault, e.g. to maintain a disaster
+// recovery copy of production secrets in a separate 1Password account.
+//
+// Syncing is one-way and split into two steps: NewPlan compares both vaults and describes the items to create,
+// update and remove, without changing anything, and Plan.Apply carries out the plan. Destination items are matched
+// to source items by title and category, or by a tag holding the ID of the source item (see Options.IDTagPrefix),
+// which keeps them matched when items are renamed.
+package vaultsync
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/1password/onepassword-sdk-go"
+)
+
+// Vault identifies a vault, together with the client of the account it belongs to.
+type Vault struct {
+	Client  *onepassword.Client
+	VaultID string
+}
+
+// ExtrasAction is what happens to destination items that have no counterpart in the source vault.
+type ExtrasAction string
+
+const (
+	// Leave extra items untouched
+	ExtrasKeep ExtrasAction = ""
+	// Archive extra items
+	ExtrasArchive ExtrasAction = "archive"
+	// Delete extra items
+	ExtrasDelete ExtrasAction = "delete"
+)
+
+// Options configure NewPlan.
+type Options struct {
+	// If set, items are matched by a tag made of this prefix followed by the ID of the source item, e.g. "synced-from:",
+	// which is added to every destination item. Destination items without such a tag aren't managed by the sync.
+	// Otherwise items are matched by title and category, and every destination item is managed by the sync.
+	IDTagPrefix string
+	// What happens to managed destination items that have no counterpart in the source vault
+	Extras ExtrasAction
+}
+
+// ActionType is the kind of change an Action makes to the destination vault.
+type ActionType string
+
+const (
+	ActionCreate  ActionType = "create"
+	ActionUpdate  ActionType = "update"
+	ActionArchive ActionType = "archive"
+	ActionDelete  ActionType = "delete"
+)
+
+// Action is a change to a single destination item.
+type Action struct {
+	Type ActionType
+	// The title of the item
+	Title string
+	// The category of the item
+	Category onepassword.ItemCategory
+	// The ID of the source item, empty for archived and deleted items
+	SourceItemID string
+	// The ID of the destination item, empty for created items
+	DestinationItemID string
+	// The changes made to the destination item, only set for updated items
+	Diff onepassword.ItemDiff
+
+	source      onepassword.Item
+	destination onepassword.Item
+	// The destination files and document kept by an update, by the ID of the source file they match
+	files map[string]onepassword.FileAttributes
+}
+
+func (a Action) String() string {
+	return fmt.Sprintf("%s %s %q", a.Type, a.Category, a.Title)
+}
+
+// Skip is an item that can't be synced.
+type Skip struct {
+	// The title of the item
+	Title string
+	// The ID of the item in the vault it was found in
+	ItemID string
+	// The ID of that vault
+	VaultID string
+	// Why the item is skipped
+	Reason string
+}
+
+// Plan describes the changes needed to bring the destination vault in line with the source vault.
+type Plan struct {
+	Source      Vault
+	Destination Vault
+	// The changes to make
+	Actions []Action
+	// The number of destination items that are already in sync
+	Unchanged int
+	// Items that are left out of the sync, because their key isn't unique
+	Skipped []Skip
+
+	opts Options
+}
+
+// Empty reports whether the destination vault is already in sync.
+func (p *Plan) Empty() bool {
+	return len(p.Actions) == 0
+}
+
+// String describes the plan in a human-readable form, one line per action and change. Concealed values are masked.
+func (p *Plan) String() string {
+	var b strings.Builder
+	for _, action := range p.Actions {
+		b.WriteString(action.String())
+		b.WriteByte('\n')
+		if action.Type == ActionUpdate {
+			for _, line := range strings.Split(strings.TrimSuffix(action.Diff.String(), "\n"), "\n") {
+				b.WriteString("    ")
+				b.WriteString(line)
+				b.WriteByte('\n')
+			}
+		}
+	}
+	for _, skip := range p.Skipped {
+		fmt.Fprintf(&b, "skip %q in vault %s: %s\n", skip.Title, skip.VaultID, skip.Reason)
+	}
+	fmt.Fprintf(&b, "%d to create, %d to update, %d to remove, %d unchanged\n", p.count(ActionCreate), p.count(ActionUpdate), p.count(ActionArchive)+p.count(ActionDelete), p.Unchanged)
+	return b.String()
+}
+
+func (p *Plan) count(t ActionType) int {
+	n := 0
+	for _, action := range p.Actions {
+		if action.Type == t {
+			n++
+		}
+	}
+	return n
+}
+
+// NewPlan compares the active items of the source and destination vaults and plans the changes needed to mirror
+// the source into the destination. It doesn't change either vault.
+// Files of matching items are compared by content, so files without a recorded hash (see onepassword.FileHash) are
+// read from both vaults.
+func NewPlan(ctx context.Context, source Vault, destination Vault, opts Options) (*Plan, error) {
+	plan := &Plan{Source: source, Destination: destination, opts: opts}
+
+	sourceItems, err := getItems(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	destinationItems, err := getItems(ctx, destination)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceByKey, sourceDuplicates := plan.index(source, sourceItems, plan.sourceKey)
+	destinationByKey, destinationDuplicates := plan.index(destination, destinationItems, plan.destinationKey)
+
+	for _, item := range sourceItems {
+		key, _ := plan.sourceKey(item)
+		if sourceDuplicates[key] || destinationDuplicates[key] {
+			continue
+		}
+		dest, ok := destinationByKey[key]
+		if !ok {
+			plan.Actions = append(plan.Actions, Action{
+				Type:         ActionCreate,
+				Title:        item.Title,
+				Category:     item.Category,
+				SourceItemID: item.ID,
+				source:       item,
+			})
+			continue
+		}
+
+		files, err := matchFiles(ctx, source, item, destination, dest)
+		if err != nil {
+			return nil, err
+		}
+		diff := onepassword.DiffItems(dest, plan.desired(item, dest, files))
+		if diff.Empty() {
+			plan.Unchanged++
+			continue
+		}
+		plan.Actions = append(plan.Actions, Action{
+			Type:              ActionUpdate,
+			Title:             item.Title,
+			Category:          item.Category,
+			SourceItemID:      item.ID,
+			DestinationItemID: dest.ID,
+			Diff:              diff,
+			source:            item,
+			destination:       dest,
+			files:             files,
+		})
+	}
+
+	if opts.Extras != ExtrasKeep {
+		actionType := ActionArchive
+		if opts.Extras == ExtrasDelete {
+			actionType = ActionDelete
+		}
+		for _, item := range destinationItems {
+			key, managed := plan.destinationKey(item)
+			if !managed || destinationDuplicates[key] || sourceDuplicates[key] {
+				continue
+			}
+			if _, ok := sourceByKey[key]; ok {
+				continue
+			}
+			plan.Actions = append(plan.Actions, Action{
+				Type:              actionType,
+				Title:             item.Title,
+				Category:          item.Category,
+				DestinationItemID: item.ID,
+				destination:       item,
+			})
+		}
+	}
+
+	return plan, nil
+}
+
+// sourceKey returns the key a source item is matched by.
+func (p *Plan) sourceKey(item onepassword.Item) (string, bool) {
+	if p.opts.IDTagPrefix != "" {
+		return item.ID, true
+	}
+	return titleKey(item), true
+}
+
+// destinationKey returns the key a destination item is matched by, and whether the item is managed by the sync.
+func (p *Plan) destinationKey(item onepassword.Item) (string, bool) {
+	if p.opts.IDTagPrefix == "" {
+		return titleKey(item), true
+	}
+	for _, tag := range item.Tags {
+		if id, ok := strings.CutPrefix(tag, p.opts.IDTagPrefix); ok && id != "" {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+func titleKey(item onepassword.Item) string {
+	return string(item.Category) + "/" + item.Title
+}
+
+// index maps the items of a vault by key. Items whose key isn't unique are left out and reported as skipped.
+func (p *Plan) index(vault Vault, items []onepassword.Item, key func(onepassword.Item) (string, bool)) (map[string]onepassword.Item, map[string]bool) {
+	byKey := map[string]onepassword.Item{}
+	counts := map[string]int{}
+	for _, item := range items {
+		if k, ok := key(item); ok {
+			counts[k]++
+			byKey[k] = item
+		}
+	}
+
+	duplicates := map[string]bool{}
+	for _, item := range items {
+		if k, ok := key(item); ok && counts[k] > 1 {
+			delete(byKey, k)
+			duplicates[k] = true
+			p.Skipped = append(p.Skipped, Skip{
+				Title:   item.Title,
+				ItemID:  item.ID,
+				VaultID: vault.VaultID,
+				Reason:  fmt.Sprintf("%d items in the vault match the same key", counts[k]),
+			})
+		}
+	}
+	return byKey, duplicates
+}
@@ -0,0 +1,198 @@
+package vaultsync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/1password/onepassword-sdk-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeItems struct {
+	onepassword.ItemsAPI
+	items map[string]onepassword.Item
+	order []string
+	files fakeFiles
+}
+
+// fakeFiles serves the content of files by file ID, and records the files it read.
+type fakeFiles struct {
+	onepassword.ItemsFilesAPI
+	content map[string][]byte
+	read    *[]string
+}
+
+func (f fakeFiles) Read(ctx context.Context, vaultID string, itemID string, attr onepassword.FileAttributes) ([]byte, error) {
+	*f.read = append(*f.read, attr.ID)
+	return f.content[attr.ID], nil
+}
+
+func newFakeItems(items ...onepassword.Item) *fakeItems {
+	f := &fakeItems{items: map[string]onepassword.Item{}}
+	for _, item := range items {
+		f.items[item.ID] = item
+		f.order = append(f.order, item.ID)
+	}
+	return f
+}
+
+func (f *fakeItems) List(ctx context.Context, vaultID string, filters ...onepassword.ItemListFilter) ([]onepassword.ItemOverview, error) {
+	var overviews []onepassword.ItemOverview
+	for _, id := range f.order {
+		if item, ok := f.items[id]; ok {
+			overviews = append(overviews, onepassword.ItemOverview{ID: item.ID, Title: item.Title, Category: item.Category, VaultID: vaultID})
+		}
+	}
+	return overviews, nil
+}
+
+func (f *fakeItems) GetAll(ctx context.Context, vaultID string, itemIDs []string) (onepassword.ItemsGetAllResponse, error) {
+	var response onepassword.ItemsGetAllResponse
+	for _, id := range itemIDs {
+		item := f.items[id]
+		response.IndividualResponses = append(response.IndividualResponses, onepassword.Response[onepassword.Item, onepassword.ItemsGetAllError]{Content: &item})
+	}
+	return response, nil
+}
+
+func (f *fakeItems) CreateAll(ctx context.Context, vaultID string, params []onepassword.ItemCreateParams) (onepassword.ItemsUpdateAllResponse, error) {
+	var response onepassword.ItemsUpdateAllResponse
+	for _, p := range params {
+		item := onepassword.Item{ID: "new-" + p.Title, Title: p.Title, Category: p.Category, VaultID: vaultID, Fields: p.Fields, Tags: p.Tags}
+		f.items[item.ID] = item
+		f.order = append(f.order, item.ID)
+		response.IndividualResponses = append(response.IndividualResponses, onepassword.Response[onepassword.Item, onepassword.ItemUpdateFailureReason]{Content: &item})
+	}
+	return response, nil
+}
+
+func (f *fakeItems) Put(ctx context.Context, item onepassword.Item) (onepassword.Item, error) {
+	item.Version++
+	f.items[item.ID] = item
+	return item, nil
+}
+
+func (f *fakeItems) Delete(ctx context.Context, vaultID string, itemID string) error {
+	delete(f.items, itemID)
+	return nil
+}
+
+func (f *fakeItems) Files() onepassword.ItemsFilesAPI {
+	return f.files
+}
+
+func TestSync(t *testing.T) {
+	ctx := context.Background()
+	password := func(value string) []onepassword.ItemField {
+		return []onepassword.ItemField{{ID: "password", Title: "password", FieldType: onepassword.ItemFieldTypeConcealed, Value: value}}
+	}
+
+	source := newFakeItems(
+		onepassword.Item{ID: "s1", Title: "Database", Category: onepassword.ItemCategoryDatabase, Fields: password("new")},
+		onepassword.Item{ID: "s2", Title: "API", Category: onepassword.ItemCategoryAPICredentials, Fields: password("token")},
+		onepassword.Item{ID: "s3", Title: "Unchanged", Category: onepassword.ItemCategoryPassword, Fields: password("same")},
+	)
+	destination := newFakeItems(
+		onepassword.Item{ID: "d1", Title: "Database", Category: onepassword.ItemCategoryDatabase, Fields: password("old")},
+		onepassword.Item{ID: "d3", Title: "Unchanged", Category: onepassword.ItemCategoryPassword, Fields: password("same")},
+		onepassword.Item{ID: "d4", Title: "Legacy", Category: onepassword.ItemCategoryLogin},
+	)
+
+	plan, err := NewPlan(ctx,
+		Vault{Client: &onepassword.Client{ItemsAPI: source}, VaultID: "production"},
+		Vault{Client: &onepassword.Client{ItemsAPI: destination}, VaultID: "recovery"},
+		Options{Extras: ExtrasDelete},
+	)
+	require.NoError(t, err)
+	require.Len(t, plan.Actions, 3)
+	assert.Equal(t, 1, plan.Unchanged)
+
+	assert.Equal(t, ActionUpdate, plan.Actions[0].Type)
+	assert.Equal(t, "d1", plan.Actions[0].DestinationItemID)
+	require.Len(t, plan.Actions[0].Diff.Changes, 1)
+	assert.Equal(t, "********", plan.Actions[0].Diff.Changes[0].New)
+	assert.Equal(t, ActionCreate, plan.Actions[1].Type)
+	assert.Equal(t, "s2", plan.Actions[1].SourceItemID)
+	assert.Equal(t, ActionDelete, plan.Actions[2].Type)
+	assert.Equal(t, "d4", plan.Actions[2].DestinationItemID)
+	assert.NotContains(t, plan.String(), "new")
+
+	report, err := plan.Apply(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, report.Failed)
+	assert.Len(t, report.Created, 1)
+	assert.Len(t, report.Updated, 1)
+	assert.Equal(t, []string{"d4"}, report.Removed)
+	assert.Equal(t, "new", destination.items["d1"].Fields[0].Value)
+
+	plan, err = NewPlan(ctx,
+		Vault{Client: &onepassword.Client{ItemsAPI: source}, VaultID: "production"},
+		Vault{Client: &onepassword.Client{ItemsAPI: destination}, VaultID: "recovery"},
+		Options{Extras: ExtrasDelete},
+	)
+	require.NoError(t, err)
+	assert.True(t, plan.Empty())
+}
+
+func TestPlanComparesFileContent(t *testing.T) {
+	ctx := context.Background()
+	file := func(id string) []onepassword.ItemFile {
+		return []onepassword.ItemFile{{Attributes: onepassword.FileAttributes{ID: id, Name: "config.yaml", Size: 3}, SectionID: "files", FieldID: "config"}}
+	}
+	document := func(id string) *onepassword.FileAttributes {
+		return &onepassword.FileAttributes{ID: id, Name: "runbook.pdf", Size: 4}
+	}
+
+	var read []string
+	source := newFakeItems(
+		onepassword.Item{ID: "s1", Title: "Changed file", Category: onepassword.ItemCategorySecureNote, Files: file("sf1")},
+		onepassword.Item{ID: "s2", Title: "Same file", Category: onepassword.ItemCategorySecureNote, Files: file("sf2")},
+		onepassword.Item{ID: "s3", Title: "Changed document", Category: onepassword.ItemCategoryDocument, Document: document("sd3")},
+	)
+	source.files = fakeFiles{content: map[string][]byte{"sf1": []byte("new"), "sf2": []byte("abc"), "sd3": []byte("v2.0")}, read: &read}
+	destination := newFakeItems(
+		onepassword.Item{ID: "d1", Title: "Changed file", Category: onepassword.ItemCategorySecureNote, Files: file("df1")},
+		onepassword.Item{ID: "d2", Title: "Same file", Category: onepassword.ItemCategorySecureNote, Files: file("df2")},
+		onepassword.Item{ID: "d3", Title: "Changed document", Category: onepassword.ItemCategoryDocument, Document: document("dd3")},
+	)
+	destination.files = fakeFiles{content: map[string][]byte{"df1": []byte("old"), "df2": []byte("abc"), "dd3": []byte("v1.0")}, read: &read}
+
+	plan, err := NewPlan(ctx,
+		Vault{Client: &onepassword.Client{ItemsAPI: source}, VaultID: "production"},
+		Vault{Client: &onepassword.Client{ItemsAPI: destination}, VaultID: "recovery"},
+		Options{},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 1, plan.Unchanged, "files with the same content are kept")
+	require.Len(t, plan.Actions, 2)
+	assert.Equal(t, ActionUpdate, plan.Actions[0].Type)
+	assert.Equal(t, "d1", plan.Actions[0].DestinationItemID)
+	require.Len(t, plan.Actions[0].Diff.Changes, 2, "the old file is removed and the new one added")
+	assert.Equal(t, onepassword.ItemChangeTargetFile, plan.Actions[0].Diff.Changes[0].Target)
+	assert.Equal(t, ActionUpdate, plan.Actions[1].Type)
+	assert.Equal(t, "d3", plan.Actions[1].DestinationItemID)
+	assert.Equal(t, onepassword.ItemChangeTargetDocument, plan.Actions[1].Diff.Changes[0].Target)
+	assert.ElementsMatch(t, []string{"sf1", "df1", "sf2", "df2", "sd3", "dd3"}, read)
+
+	// Recorded hashes are used instead of reading the files
+	read = nil
+	hashed := func(item onepassword.Item, content []byte) onepassword.Item {
+		params := onepassword.WithFileHashes(onepassword.ItemCreateParams{
+			Files: []onepassword.FileCreateParams{{Name: "config.yaml", Content: content, SectionID: "files", FieldID: "config"}},
+		})
+		item.Sections, item.Fields = params.Sections, params.Fields
+		return item
+	}
+	source.items["s2"] = hashed(source.items["s2"], []byte("abc"))
+	destination.items["d2"] = hashed(destination.items["d2"], []byte("abc"))
+	plan, err = NewPlan(ctx,
+		Vault{Client: &onepassword.Client{ItemsAPI: source}, VaultID: "production"},
+		Vault{Client: &onepassword.Client{ItemsAPI: destination}, VaultID: "recovery"},
+		Options{},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 1, plan.Unchanged)
+	assert.NotContains(t, read, "sf2")
+	assert.NotContains(t, read, "df2")
+}